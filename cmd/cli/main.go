@@ -1,12 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"advent2024/pkg/parse"
 	"advent2024/pkg/solver"
+	"advent2024/pkg/wasm"
 
 	_ "advent2024/pkg/d1"
 	_ "advent2024/pkg/d10"
@@ -23,51 +32,520 @@ import (
 
 var Version string = "dev"
 
+// Deadline of parsing the input by one solver during day detection
+const detectTimeout = 2 * time.Second
+
 func main() {
 
 	filename := flag.String("filename", "", "Specify filename with puzzle input")
-	part := flag.Int("part", 1, "Specify which puzzle part to run")
+	part := flag.String("part", "1", "Specify which puzzle part to run, all runs every part")
 	day := flag.String("day", "d1", "Specify which day to run")
+	variant := flag.String("variant", "", "Specify which variant of the part to run, see -info")
 	version := flag.Bool("version", false, "List version")
+	list := flag.Bool("list", false, "List available solvers")
+	info := flag.Bool("info", false, "Describe puzzle solved for the day")
+	example := flag.Bool("example", false, "Use example input of the day instead of a file")
+	step := flag.Bool("step", false, "Solve stepwise, prints state after every step")
+	progress := flag.Bool("progress", false, "Draw progress bar of solvers reporting progress")
+	maxSteps := flag.Int("max-steps", 0, "Maximum number of steps printed in stepwise solving, 0 means no limit")
+	solverDir := flag.String("solver-dir", "", "Load executables in the directory as external solvers")
+	wasmDir := flag.String("wasm-dir", "", "Load .wasm modules in the directory as sandboxed solvers")
+	params := paramFlag{}
+	flag.Var(params, "param", "Set parameter of the solver as name=value, repeatable, see -info")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Version %s\n\n", Version)
+		fmt.Fprintf(os.Stderr, "Usage: %s [validate|detect|crosscheck] [flags]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  validate only parses the input and prints its summary\n")
+		fmt.Fprintf(os.Stderr, "  detect lists days which might solve the input, best first\n")
+		fmt.Fprintf(os.Stderr, "  crosscheck solves the part with every variant and compares them\n\n")
 		flag.PrintDefaults()
 	}
-	flag.Parse()
+
+	// optional command precedes the flags
+	args := os.Args[1:]
+	command := ""
+
+	if len(args) > 0 && (args[0] == "validate" || args[0] == "detect" || args[0] == "crosscheck") {
+		command, args = args[0], args[1:]
+	}
+
+	flag.CommandLine.Parse(args)
 
 	if *version == true {
 		flag.Usage()
 		os.Exit(0)
 	}
 
-	solver, ok := solver.New(*day)
+	if *solverDir != "" {
+		if _, err := solver.LoadExternal(context.Background(), *solverDir); err != nil {
+			log.Println(err)
+		}
+	}
+
+	if *wasmDir != "" {
+		loadWasm(*wasmDir)
+	}
+
+	if *list {
+		printList()
+		return
+	}
+
+	if *info {
+		printInfo(*day)
+		return
+	}
+
+	// input is kept in memory to point to the invalid line
+	var text string
+
+	if *example {
+		item, ok := solver.Lookup(*day)
+
+		if !ok {
+			log.Fatal("Unable to find solver for day ", *day)
+		}
+
+		text = item.Metadata.Example
+	} else {
+		data, err := os.ReadFile(*filename)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		text = string(data)
+	}
+
+	input := strings.NewReader(text)
+
+	// parameters are validated before the solver is initialized
+	ctx := context.Background()
+
+	if item, ok := solver.Lookup(*day); ok {
+		p, err := item.Metadata.ParseParams(params)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		ctx = solver.WithParams(ctx, p)
+	}
+
+	switch command {
+	case "validate":
+		runValidate(ctx, *day, input, text)
+		return
+	case "detect":
+		runDetect(input)
+		return
+	case "crosscheck":
+		runCrosscheck(ctx, *day, *part, input, text)
+		return
+	}
+
+	if *step {
+		runSteps(ctx, *day, input, text, *maxSteps)
+		return
+	}
+
+	var bar *progressBar
+
+	if *progress {
+		bar = newProgressBar(os.Stderr)
+	}
+
+	if *part == "all" {
+		if bar != nil {
+			ctx = solver.WithProgress(ctx, bar.Update)
+		}
+
+		runAll(ctx, *day, input, text, bar)
+		return
+	}
+
+	partNum, err := strconv.Atoi(*part)
+
+	if err != nil {
+		log.Fatal("Invalid part ", *part)
+	}
+
+	if bar != nil {
+		ctx = solver.WithProgress(ctx, func(p solver.Progress) {
+			p.Part = partNum
+			bar.Update(p)
+		})
+	}
+
+	s, ok := solver.NewWithCtx(*day)
 
 	if !ok {
 		log.Fatal("Unable to find solver for day ", *day)
 	}
 
-	fh, err := os.Open(*filename)
+	start := time.Now()
+	err = s.InitCtx(ctx, input)
+	parseTime := time.Since(start)
+
+	if err != nil {
+		fatalInput(err, text)
+	}
+
+	result, err := solver.SolveVariant(ctx, s, partNum, *variant)
+	result.ParseTime = parseTime
+
+	if bar != nil {
+		bar.Finish()
+	}
+
+	if err != nil {
+		fatal(err)
+	}
+
+	printResult(result)
+
+	//bumptest3
+}
+
+// Prints registered solvers with their parts
+func printList() {
+	for _, item := range solver.ListRegistryItems() {
+		parts := make([]string, 0, len(item.Parts))
+
+		for _, p := range item.Parts {
+			if len(p.Variants) > 0 {
+				parts = append(parts, fmt.Sprintf("%d (%s)", p.Part, strings.Join(p.Variants, ", ")))
+				continue
+			}
+
+			parts = append(parts, strconv.Itoa(p.Part))
+		}
+
+		fmt.Printf("%-4s %-24s parts %s\n", item.Name, item.Title, strings.Join(parts, ", "))
+	}
+}
+
+// Prints description of the puzzle solved for the day
+func printInfo(day string) {
+	item, ok := solver.Lookup(day)
+
+	if !ok {
+		log.Fatal("Unable to find solver for day ", day)
+	}
+
+	m := item.Metadata
+
+	fmt.Printf("%s: %s\n", item.Name, m.Title)
+
+	if m.Description != "" {
+		fmt.Printf("\n%s\n", m.Description)
+	}
+
+	fmt.Printf("\nParts:\n")
+	for _, p := range m.Parts {
+		fmt.Printf("  %d: %s", p.Part, p.Label)
+		if p.ExampleAnswer != "" {
+			fmt.Printf(" (example answer %s)", p.ExampleAnswer)
+		}
+		fmt.Println()
+		if len(p.Variants) > 0 {
+			fmt.Printf("     variants: %s\n", strings.Join(p.Variants, ", "))
+		}
+	}
+
+	if len(m.Params) > 0 {
+		fmt.Printf("\nParameters:\n")
+		for _, p := range m.Params {
+			fmt.Printf("  %s (%s, default %q, bounds %s): %s\n", p.Name, p.Type, p.Default, p.Bounds(), p.Description)
+		}
+	}
+
+	if m.InputFormat != "" {
+		fmt.Printf("\nInput format:\n  %s\n", m.InputFormat)
+	}
+
+	if m.Example != "" {
+		fmt.Printf("\nExample:\n%s\n", m.Example)
+	}
+}
+
+// Loads sandboxed solvers, the runtime is kept open until exit
+func loadWasm(dir string) {
+	ctx := context.Background()
+
+	rt, err := wasm.NewRuntime(ctx, wasm.NewConfig())
 
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	defer fh.Close()
+	if _, err := rt.Load(ctx, dir); err != nil {
+		log.Println(err)
+	}
+}
+
+// Solves all parts of the puzzle concurrently
+// Prints result of every part, exits with error if any part failed
+func runAll(ctx context.Context, day string, input io.Reader, text string, bar *progressBar) {
+	results, err := solver.SolveAll(ctx, day, input)
 
-	err = solver.Init(fh)
+	if bar != nil {
+		bar.Finish()
+	}
+
+	if err != nil {
+		fatalInput(err, text)
+	}
+
+	failed := false
+
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Printf("Error - Part %d: %v\n", r.Part, r.Err)
+			printPanic(r.Err)
+			failed = true
+			continue
+		}
+
+		printResult(r.Result)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// Logs err and exits, points to the invalid line of the input if known
+func fatalInput(err error, text string) {
+	var ie *parse.InputError
+
+	if !errors.As(err, &ie) || ie.Line < 1 {
+		fatal(err)
+	}
+
+	lines := strings.Split(text, "\n")
+
+	if ie.Line > len(lines) {
+		fatal(err)
+	}
+
+	line := strings.TrimRight(lines[ie.Line-1], "\r")
+	prefix := fmt.Sprintf("%5d | ", ie.Line)
+
+	fmt.Fprintf(os.Stderr, "%s%s\n", prefix, line)
+
+	if ie.Column > 0 {
+		pointer := strings.Repeat(" ", len(prefix)+ie.Column-1) + "^"
+
+		if ie.Expected != "" {
+			pointer += " expected " + ie.Expected
+		}
+
+		fmt.Fprintln(os.Stderr, pointer)
+	} else if ie.Expected != "" {
+		fmt.Fprintf(os.Stderr, "%*s expected %s\n", len(prefix)-1, "|", ie.Expected)
+	}
+
+	fatal(err)
+}
+
+// Logs err and exits, prints stack of the panicking solver
+func fatal(err error) {
+	printPanic(err)
+	log.Fatal(err)
+}
+
+// Prints stack of the panicking solver if err is a recovered panic
+func printPanic(err error) {
+	var pe *solver.PanicError
+
+	if errors.As(err, &pe) {
+		fmt.Fprintf(os.Stderr, "%s\n", pe.Stack)
+	}
+}
+
+// Parses the input without solving
+// Prints summary of valid input, points to the error otherwise
+func runValidate(ctx context.Context, day string, input io.Reader, text string) {
+	v, err := solver.Validate(ctx, day, input)
 
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	result, err := solver.Solve(*part)
+	if !v.Valid() {
+		fatalInput(v.Err, text)
+	}
+
+	fmt.Printf("Input valid for %s\n", day)
+	fmt.Printf("  Parse time: %s\n", v.ParseTime)
+
+	keys := make([]string, 0, len(v.Facts))
+	for k := range v.Facts {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		fmt.Printf("  %-11s %d\n", k+":", v.Facts[k])
+	}
+}
+
+// Detects the day of the input
+// Prints days which parsed the input with their score, best first
+func runDetect(input io.Reader) {
+	candidates, err := solver.Detect(context.Background(), input, detectTimeout)
 
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	fmt.Printf("Result - Part %d: %s", *part, result)
+	if len(candidates) == 0 {
+		log.Fatal("No solver is able to parse the input")
+	}
 
-	//bumptest3
+	for _, c := range candidates {
+		item, _ := solver.Lookup(c.Name)
+		fmt.Printf("%-4s %-24s score %.2f\n", c.Name, item.Metadata.Title, c.Score)
+	}
+}
+
+// Solves the part with every variant on the same input
+// Prints output and solve time of every variant relative to the first
+// successful one, exits with error if any variant disagrees or fails
+func runCrosscheck(ctx context.Context, day string, part string, input io.Reader, text string) {
+	partNum, err := strconv.Atoi(part)
+
+	if err != nil {
+		log.Fatal("Invalid part ", part)
+	}
+
+	c, err := solver.CrosscheckPart(ctx, day, partNum, input)
+
+	if err != nil {
+		fatalInput(err, text)
+	}
+
+	reference, _ := c.Reference()
+	first := reference.SolveTime
+
+	fmt.Printf("Crosscheck - Part %d\n", c.Part)
+
+	for _, r := range c.Results {
+		if r.Err != nil {
+			fmt.Printf("  %-12s error: %v\n", r.Variant, r.Err)
+			printPanic(r.Err)
+			continue
+		}
+
+		ratio := 0.0
+		if first > 0 {
+			ratio = float64(r.SolveTime) / float64(first)
+		}
+
+		fmt.Printf("  %-12s %-20s %12s %6.2fx\n", r.Variant, r.Output, r.SolveTime, ratio)
+	}
+
+	if len(c.Failed) > 0 {
+		fmt.Printf("Variants failed: %s\n", strings.Join(c.Failed, ", "))
+	}
+
+	if len(c.Disagree) > 0 {
+		fmt.Printf("Variants disagree: %s\n", strings.Join(c.Disagree, ", "))
+	}
+
+	if !c.Agree() {
+		os.Exit(1)
+	}
+
+	fmt.Printf("Variants agree\n")
+}
+
+// Prints result with its value, times, allocations and statistics
+func printResult(r solver.Result) {
+	fmt.Printf("Result - Part %d: %s\n", r.Part, r.Output)
+	if r.Variant != "" {
+		fmt.Printf("  Variant:    %s\n", r.Variant)
+	}
+	fmt.Printf("  Value:      %v (%s)\n", r.Value, solver.ValueType(r.Value))
+	fmt.Printf("  Parse time: %s\n", r.ParseTime)
+	fmt.Printf("  Solve time: %s\n", r.SolveTime)
+	usage := ""
+	if r.ProcessWide {
+		usage = " (process-wide approximation)"
+	}
+	fmt.Printf("  CPU time:   %s%s\n", r.CPUTime, usage)
+	fmt.Printf("  Allocated:  %d B%s\n", r.Allocated, usage)
+
+	keys := make([]string, 0, len(r.Stats))
+	for k := range r.Stats {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		fmt.Printf("  %-11s %d\n", k+":", r.Stats[k])
+	}
+}
+
+// Solves the puzzle stepwise
+// Prints state after every step until the solver is finished or limit is reached
+func runSteps(ctx context.Context, day string, input io.Reader, text string, maxSteps int) {
+	s, ok := solver.NewWithCtx(day)
+
+	if !ok {
+		log.Fatal("Unable to find solver for day ", day)
+	}
+
+	stepper, ok := s.(solver.StepperWithCtx)
+
+	if !ok {
+		log.Fatal("Stepwise solving not supported for day ", day)
+	}
+
+	if err := stepper.InitCtx(ctx, input); err != nil {
+		fatalInput(err, text)
+	}
+
+	for i := 1; maxSteps == 0 || i <= maxSteps; i++ {
+		state, err := stepper.Next(ctx)
+
+		if errors.Is(err, solver.ErrNoMoreSteps) {
+			return
+		}
+
+		if err != nil {
+			fatal(err)
+		}
+
+		fmt.Printf("Step %d: %s\n", i, state)
+	}
+}
+
+// Parameters of the solver given by repeated -param name=value flags
+type paramFlag map[string]string
+
+// Returns parameters in the form of the flag
+func (f paramFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for name, value := range f {
+		pairs = append(pairs, name+"="+value)
+	}
+	slices.Sort(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// Adds parameter given as name=value
+func (f paramFlag) Set(value string) error {
+	name, v, ok := strings.Cut(value, "=")
+
+	if !ok || name == "" {
+		return fmt.Errorf("parameter %q is not name=value", value)
+	}
+
+	f[name] = v
+
+	return nil
 }
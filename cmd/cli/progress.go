@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"advent2024/pkg/solver"
+)
+
+// Width of the drawn progress bar
+const progressBarWidth = 30
+
+// Progress bar drawn on one line, one bar per part
+type progressBar struct {
+	mu    sync.Mutex
+	out   io.Writer
+	parts map[int]solver.Progress
+	line  string
+}
+
+// Constructor
+func newProgressBar(out io.Writer) *progressBar {
+	return &progressBar{out: out, parts: make(map[int]solver.Progress)}
+}
+
+// Updates progress of the part and redraws the line if it changed
+func (b *progressBar) Update(p solver.Progress) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.parts[p.Part] = p
+
+	keys := make([]int, 0, len(b.parts))
+	for k := range b.parts {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	bars := make([]string, 0, len(keys))
+	for _, k := range keys {
+		bars = append(bars, renderBar(b.parts[k]))
+	}
+
+	line := strings.Join(bars, "  ")
+
+	if line == b.line {
+		return
+	}
+
+	b.line = line
+	fmt.Fprintf(b.out, "\r%s", line)
+}
+
+// Ends the line if anything was drawn
+func (b *progressBar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.line != "" {
+		fmt.Fprintln(b.out)
+	}
+}
+
+// Renders progress of one part, e.g. Part 2 [#####.....] 50%
+func renderBar(p solver.Progress) string {
+	percent := 0
+	if p.Total > 0 {
+		percent = min(p.Done*100/p.Total, 100)
+	}
+
+	filled := percent * progressBarWidth / 100
+
+	return fmt.Sprintf("Part %d [%s%s] %3d%%", p.Part,
+		strings.Repeat("#", filled),
+		strings.Repeat(".", progressBarWidth-filled),
+		percent)
+}
@@ -0,0 +1,48 @@
+// Solver of one day served over the external solver protocol
+// Built as WebAssembly module for the sandbox, the day is chosen at build time:
+//
+//	GOOS=wasip1 GOARCH=wasm go build -ldflags="-X 'main.Day=d6'" -o d6.wasm
+//
+// Name overrides the registered name, so the module can be served next to
+// the built-in solver of the day, e.g. -X 'main.Name=d6-wasm'
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"advent2024/pkg/solver"
+
+	_ "advent2024/pkg/d1"
+	_ "advent2024/pkg/d10"
+	_ "advent2024/pkg/d11"
+	_ "advent2024/pkg/d2"
+	_ "advent2024/pkg/d3"
+	_ "advent2024/pkg/d4"
+	_ "advent2024/pkg/d5"
+	_ "advent2024/pkg/d6"
+	_ "advent2024/pkg/d7"
+	_ "advent2024/pkg/d8"
+	_ "advent2024/pkg/d9"
+)
+
+var Day string = "d1"
+var Name string
+
+func main() {
+	item, ok := solver.Lookup(Day)
+
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unable to find solver for day %s\n", Day)
+		os.Exit(1)
+	}
+
+	if Name == "" {
+		Name = item.Name
+	}
+
+	if err := solver.ServeExternal(os.Stdin, os.Stdout, Name, item.Constructor); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -4,6 +4,7 @@ package api
 import (
 	"advent2024/pkg/solver"
 	"advent2024/web/config"
+	"advent2024/web/metrics"
 	"advent2024/web/middleware"
 	"advent2024/web/weberrors"
 	"context"
@@ -16,56 +17,863 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // API Request
+// Params are values of the parameters declared by the solver, numbers
+// or strings, they override parameters given as query fields
 type SolveRequest struct {
-	Input string `json:"input" format:"base64" example:"MyAgIDQKNCAgIDMKMiAgIDUKMSAgIDMKMyAgIDkKMyAgIDMK"`
+	Input  string                     `json:"input" format:"base64" example:"MyAgIDQKNCAgIDMKMiAgIDUKMSAgIDMKMyAgIDkKMyAgIDMK"`
+	Params map[string]json.RawMessage `json:"params,omitempty" swaggertype:"object"`
 } //@name Request
 
 // API Response
+// Value is the typed answer, bigint values are exact JSON numbers of any size,
+// integers which do not fit into JavaScript numbers should be read from Output
+// CPU time and allocated bytes are approximations of the whole process
+// during the solve when processWideUsage is set, concurrent solves included
 type SolveResult struct {
-	Output string `json:"output"`
+	Output      string           `json:"output" example:"11"`
+	Part        int              `json:"part,omitempty" example:"1"`
+	Variant     string           `json:"variant,omitempty" example:"parallel"`
+	Value       any              `json:"value,omitempty" swaggertype:"primitive,integer" example:"11"`
+	ValueType   string           `json:"valueType,omitempty" enums:"int,bigint,string" example:"int"`
+	ParseTime   float64          `json:"parseTimeMs" example:"0.25"`
+	SolveTime   float64          `json:"solveTimeMs" example:"1.5"`
+	CPUTime     float64          `json:"cpuTimeMs" example:"1.2"`
+	Allocated   uint64           `json:"allocatedBytes" example:"40960"`
+	ProcessWide bool             `json:"processWideUsage,omitempty" example:"true"`
+	Stats       map[string]int64 `json:"stats,omitempty"`
 } //@name Response
 
+// API Result of one part
+type PartResult struct {
+	SolveResult
+	Error     string `json:"error,omitempty"`
+	ErrorKind string `json:"errorKind,omitempty" enums:"invalid_input,timeout,overloaded,unknown_part,unknown_variant,invalid_param,solver_panic"`
+} //@name PartResponse
+
+// API Response with results of all parts
+type SolveAllResult struct {
+	Results []PartResult `json:"results"`
+} //@name SolveAllResponse
+
+// API Result of one variant of the part
+// TimeRatio is the solve time relative to the first successful variant
+type VariantResult struct {
+	PartResult
+	TimeRatio float64 `json:"timeRatio" example:"0.4"`
+} //@name VariantResponse
+
+// API Response with results of all variants of the part
+// Disagree lists variants which returned other output than the first successful one,
+// Failed lists variants which returned error
+type CrosscheckResult struct {
+	Part     int             `json:"part" example:"2"`
+	Agree    bool            `json:"agree" example:"true"`
+	Disagree []string        `json:"disagree,omitempty"`
+	Failed   []string        `json:"failed,omitempty"`
+	Results  []VariantResult `json:"results"`
+} //@name CrosscheckResponse
+
+// API Stepwise solve request
+// Params are values of the parameters declared by the solver like in Request
+type StepRequest struct {
+	Input  string                     `json:"input" format:"base64" example:"MTI1IDE3Cg=="`
+	Params map[string]json.RawMessage `json:"params,omitempty" swaggertype:"object"`
+	Start  int                        `json:"start" example:"0"`
+	Count  int                        `json:"count" example:"10"`
+} //@name StepRequest
+
+// API Stepwise solve response
+type StepResult struct {
+	Steps []json.RawMessage `json:"steps" swaggertype:"array,object"`
+	Done  bool              `json:"done"`
+} //@name StepResponse
+
+// API Input validation response
+// Message and Details describe invalid input, Facts summarise valid input
+type ValidateResult struct {
+	Valid     bool                    `json:"valid" example:"true"`
+	Message   string                  `json:"message,omitempty" example:"d1 line 2, column 3: not a number \"x4\": invalid input"`
+	Details   []weberrors.ErrorDetail `json:"details,omitempty"`
+	Facts     map[string]int64        `json:"facts,omitempty"`
+	ParseTime float64                 `json:"parseTimeMs" example:"0.25"`
+} //@name ValidateResponse
+
+// API Day which might solve the input
+type DetectCandidate struct {
+	Day   string  `json:"day" example:"d6"`
+	Title string  `json:"title" example:"Guard Gallivant"`
+	Score float64 `json:"score" example:"1"`
+} //@name Candidate
+
+// API Day detection response, candidates are ordered by score, best first
+type DetectResult struct {
+	Candidates []DetectCandidate `json:"candidates"`
+} //@name DetectResponse
+
+// Maximum number of steps returned by one request
+const maxStepsPerRequest = 1000
+
+// Deadline of parsing the input by one solver during day detection
+const detectTimeout = 2 * time.Second
+
 type CodeExchangeRequest struct {
 	Provider string `json:"provider"`
 	Code     string `json:"code"`
 } //@name CodeExchangeRequest
 
-type JWTToken struct {
-	Token string `json:"access_token"`
-} //@name TokenResponse
+type JWTToken struct {
+	Token string `json:"access_token"`
+} //@name TokenResponse
+
+type InfoResponse struct {
+	Version        string `json:"version"`
+	Authentication string `json:"authentication"`
+} //@name InfoResponse
+
+// Solve godoc
+//
+//	@Summary		Solves the problem
+//	@Description	Provides solution for the day and part based on input
+//	@Description	With Accept application/x-ndjson progress is streamed as lines of StreamLine,
+//	@Description	the last line carries the result or the error
+//	@Description	Parameters declared in the metadata of the solver are given as query fields or params of the body
+//	@Tags			Private
+//	@Accepts		json
+//	@Produces		json,x-ndjson
+//	@Security
+//	@Param		Authorization			header		string				true	"Bearer format, prefix with Bearer"
+//	@Param		Accept					header		string				false	"application/x-ndjson to stream progress"
+//	@Param		day						path		string				true	"Day, format d[0-9]*"	example(d1)
+//	@Param		part					path		int					true	"Problem part"			example(1)
+//	@Param		variant					query		string				false	"Variant of the part, default implementation if empty"	example(parallel)
+//	@Param		input					body		SolveRequest		true	"Solve Base64 encoded input"
+//	@Success	200						{object}	SolveResult			"Result"
+//	@Failure	400						{object}	weberrors.AoCError	"Bad Request"
+//	@Failure	401						{object}	weberrors.AoCError	"Unathorized"
+//	@Failure	404						{object}	weberrors.AoCError	"Solver for the day, part or variant not found"
+//	@Failure	429						{object}	weberrors.AoCError	"Request was Rate limited"
+//	@Failure	500						{object}	weberrors.AoCError	"Internal Server Error"
+//	@Failure	503						{object}	weberrors.AoCError	"Too many abandoned computations, retry later"
+//	@Failure	504						{object}	weberrors.AoCError	"Request took too long to compute"
+//	@Router		/solvers/{day}/{part}	[post]
+//	@Security	OAuth2AccessCode [read]
+//
+// Handles solve API endpoint
+func Solve(w http.ResponseWriter, r *http.Request) {
+
+	var rc int
+	var errMsg string
+
+	// get logger and config
+	logger := middleware.GetLogger(r)
+	cfg, ok := middleware.GetConfig(r)
+
+	// unable to get config
+	rc = http.StatusInternalServerError
+	errMsg = "configuration error: index: unable to get config"
+	if weberrors.HandleError(w, logger, weberrors.OkToError(ok), rc, errMsg) != nil {
+		return
+	}
+
+	// prepare response headers, always JSON
+	w.Header().Set("Content-Type", "application/json")
+
+	// get part and day from request URL
+	day := r.PathValue("day")
+	part := r.PathValue("part")
+	part_converted, err := strconv.Atoi(part)
+
+	rc = http.StatusBadRequest
+	errMsg = fmt.Sprintf("Solver for day %s part %s not implemented: part is not numerical", day, part)
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// read request
+	// limit the size of read response
+	r.Body = http.MaxBytesReader(w, r.Body, 1024*1024)
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// unmarshall request body
+	var p SolveRequest
+	err = json.Unmarshal(body, &p)
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body: Invalid JSON"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// decode the base64 encoded request
+	decoded_body, err := base64.StdEncoding.DecodeString(string(p.Input))
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body: Invalid Base64 encoding"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// get solver
+	slvr, ok := solver.NewWithCtx(day)
+
+	rc = http.StatusNotFound
+	errMsg = fmt.Sprintf("Solver for day %s part %s not implemented: day not implemented", day, part)
+	if weberrors.HandleError(w, logger, weberrors.OkToError(ok), rc, errMsg) != nil {
+		return
+	}
+
+	// check the part is described by the solver
+	item, _ := solver.Lookup(day)
+
+	rc = http.StatusNotFound
+	errMsg = fmt.Sprintf("Solver for day %s part %s not implemented: part not implemented", day, part)
+	if weberrors.HandleError(w, logger, weberrors.OkToError(item.Metadata.HasPart(part_converted)), rc, errMsg) != nil {
+		return
+	}
+
+	// check the variant is described by the solver
+	variant := r.URL.Query().Get("variant")
+
+	rc = http.StatusNotFound
+	errMsg = fmt.Sprintf("Solver for day %s part %s not implemented: variant %s not implemented", day, part, variant)
+	if weberrors.HandleError(w, logger, weberrors.OkToError(item.Metadata.HasVariant(part_converted, variant)), rc, errMsg) != nil {
+		return
+	}
+
+	// parameters are validated before the solver is initialized
+	params, err := solverParams(r, item.Metadata, p.Params)
+
+	rc = http.StatusBadRequest
+	errMsg = fmt.Sprintf("Invalid parameters for day %s: %v", day, err)
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// cancel request after deadline
+	ctx, cancel := context.WithTimeout(r.Context(), cfg.SolverTimeout)
+	defer cancel()
+	ctx = solver.WithParams(ctx, params)
+
+	// stream progress if requested by the client
+	stream := newProgressStream(w, r)
+
+	if stream != nil {
+		ctx = solver.WithProgress(ctx, func(p solver.Progress) {
+			p.Part = part_converted
+			stream.Progress(p)
+		})
+	}
+
+	// init
+	start := time.Now()
+	err = slvr.InitCtx(ctx, strings.NewReader(string(decoded_body)))
+	parseTime := time.Since(start)
+
+	// initialization took too long, overloaded, solver panicked or input error?
+	switch {
+	case errors.Is(err, solver.ErrTimeout):
+		rc = http.StatusGatewayTimeout
+	case errors.Is(err, solver.ErrOverloaded):
+		rc = http.StatusServiceUnavailable
+	case errors.Is(err, solver.ErrSolverPanic):
+		rc = http.StatusInternalServerError
+	default:
+		rc = http.StatusBadRequest
+	}
+
+	errMsg = fmt.Sprintf("Unable to intialize Solver for day %s", day)
+	if stream.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// try to solve
+	result, err := solver.SolveVariant(ctx, slvr, part_converted, variant)
+	result.ParseTime = parseTime
+
+	// solution took too long, overloaded or solver error?
+	switch {
+	case errors.Is(err, solver.ErrTimeout):
+		rc = http.StatusGatewayTimeout
+	case errors.Is(err, solver.ErrOverloaded):
+		rc = http.StatusServiceUnavailable
+	default:
+		rc = http.StatusInternalServerError
+	}
+
+	errMsg = fmt.Sprintf("Unable to solve for day %s part %s", day, part)
+	if stream.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	metrics.RecordSolve(day, result)
+
+	// send response
+	stream.Respond(w, logger, newSolveResult(result))
+}
+
+// SolveAll godoc
+//
+//	@Summary		Solves all parts of the problem
+//	@Description	Provides solutions for all parts of the day based on input
+//	@Description	Input is parsed once, parts are solved concurrently under one deadline
+//	@Description	Parts which failed carry the error in the result
+//	@Description	With Accept application/x-ndjson progress is streamed as lines of StreamLine,
+//	@Description	the last line carries the results or the error
+//	@Description	Parameters declared in the metadata of the solver are given as query fields or params of the body
+//	@Tags			Private
+//	@Accepts		json
+//	@Produces		json,x-ndjson
+//	@Security
+//	@Param		Authorization			header		string				true	"Bearer format, prefix with Bearer"
+//	@Param		Accept					header		string				false	"application/x-ndjson to stream progress"
+//	@Param		day						path		string				true	"Day, format d[0-9]*"	example(d1)
+//	@Param		input					body		SolveRequest		true	"Solve Base64 encoded input"
+//	@Success	200						{object}	SolveAllResult		"Results"
+//	@Failure	400						{object}	weberrors.AoCError	"Bad Request"
+//	@Failure	401						{object}	weberrors.AoCError	"Unathorized"
+//	@Failure	404						{object}	weberrors.AoCError	"Solver for the day not found"
+//	@Failure	429						{object}	weberrors.AoCError	"Request was Rate limited"
+//	@Failure	500						{object}	weberrors.AoCError	"Internal Server Error"
+//	@Failure	503						{object}	weberrors.AoCError	"Too many abandoned computations, retry later"
+//	@Failure	504						{object}	weberrors.AoCError	"Request took too long to compute"
+//	@Router		/solvers/{day}	[post]
+//	@Security	OAuth2AccessCode [read]
+//
+// Handles solve all parts API endpoint
+func SolveAll(w http.ResponseWriter, r *http.Request) {
+
+	var rc int
+	var errMsg string
+
+	// get logger and config
+	logger := middleware.GetLogger(r)
+	cfg, ok := middleware.GetConfig(r)
+
+	// unable to get config
+	rc = http.StatusInternalServerError
+	errMsg = "configuration error: index: unable to get config"
+	if weberrors.HandleError(w, logger, weberrors.OkToError(ok), rc, errMsg) != nil {
+		return
+	}
+
+	// prepare response headers, always JSON
+	w.Header().Set("Content-Type", "application/json")
+
+	// get day from request URL
+	day := r.PathValue("day")
+
+	// read request
+	// limit the size of read response
+	r.Body = http.MaxBytesReader(w, r.Body, 1024*1024)
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// unmarshall request body
+	var p SolveRequest
+	err = json.Unmarshal(body, &p)
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body: Invalid JSON"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// decode the base64 encoded request
+	decoded_body, err := base64.StdEncoding.DecodeString(string(p.Input))
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body: Invalid Base64 encoding"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// parameters are validated before the solver is initialized,
+	// unknown day is reported by the solver
+	var params solver.Params
+
+	if item, ok := solver.Lookup(day); ok {
+		params, err = solverParams(r, item.Metadata, p.Params)
+	}
+
+	rc = http.StatusBadRequest
+	errMsg = fmt.Sprintf("Invalid parameters for day %s: %v", day, err)
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// cancel request after deadline
+	ctx, cancel := context.WithTimeout(r.Context(), cfg.SolverTimeout)
+	defer cancel()
+	ctx = solver.WithParams(ctx, params)
+
+	// stream progress if requested by the client
+	stream := newProgressStream(w, r)
+
+	if stream != nil {
+		ctx = solver.WithProgress(ctx, stream.Progress)
+	}
+
+	// init once, solve all parts
+	results, err := solver.SolveAll(ctx, day, strings.NewReader(string(decoded_body)))
+
+	// unknown day, initialization took too long, overloaded, solver panicked or input error?
+	switch {
+	case errors.Is(err, solver.ErrUnknownSolver):
+		rc = http.StatusNotFound
+		errMsg = fmt.Sprintf("Solver for day %s not implemented: day not implemented", day)
+	case errors.Is(err, solver.ErrTimeout):
+		rc = http.StatusGatewayTimeout
+		errMsg = fmt.Sprintf("Unable to intialize Solver for day %s", day)
+	case errors.Is(err, solver.ErrOverloaded):
+		rc = http.StatusServiceUnavailable
+		errMsg = fmt.Sprintf("Unable to intialize Solver for day %s", day)
+	case errors.Is(err, solver.ErrSolverPanic):
+		rc = http.StatusInternalServerError
+		errMsg = fmt.Sprintf("Unable to intialize Solver for day %s", day)
+	default:
+		rc = http.StatusBadRequest
+		errMsg = fmt.Sprintf("Unable to intialize Solver for day %s", day)
+	}
+
+	if stream.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// prepare response
+	response := SolveAllResult{Results: make([]PartResult, 0, len(results))}
+
+	for _, result := range results {
+		partResult := PartResult{SolveResult: newSolveResult(result.Result)}
+
+		if result.Err != nil {
+			logger.Printf("Unable to solve for day %s part %d: %v", day, result.Part, result.Err)
+			partResult.Error = result.Err.Error()
+			partResult.ErrorKind = weberrors.Kind(result.Err)
+		} else {
+			metrics.RecordSolve(day, result.Result)
+		}
+
+		response.Results = append(response.Results, partResult)
+	}
+
+	// send response
+	stream.Respond(w, logger, response)
+}
+
+// Crosscheck godoc
+//
+//	@Summary		Cross-checks variants of the part
+//	@Description	Solves the part with every variant on the same input, one after another
+//	@Description	Reports variants which fail or disagree with the first successful one and their solve times
+//	@Description	Parameters declared in the metadata of the solver are given as query fields or params of the body
+//	@Tags			Private
+//	@Accepts		json
+//	@Produces		json
+//	@Security
+//	@Param		Authorization			header		string				true	"Bearer format, prefix with Bearer"
+//	@Param		day						path		string				true	"Day, format d[0-9]*"	example(d8)
+//	@Param		part					path		int					true	"Problem part"			example(2)
+//	@Param		input					body		SolveRequest		true	"Solve Base64 encoded input"
+//	@Success	200						{object}	CrosscheckResult	"Results of the variants"
+//	@Failure	400						{object}	weberrors.AoCError	"Bad Request"
+//	@Failure	401						{object}	weberrors.AoCError	"Unathorized"
+//	@Failure	404						{object}	weberrors.AoCError	"Solver for the day or variants of the part not found"
+//	@Failure	429						{object}	weberrors.AoCError	"Request was Rate limited"
+//	@Failure	500						{object}	weberrors.AoCError	"Internal Server Error"
+//	@Failure	503						{object}	weberrors.AoCError	"Too many abandoned computations, retry later"
+//	@Failure	504						{object}	weberrors.AoCError	"Request took too long to compute"
+//	@Router		/solvers/{day}/{part}/crosscheck	[post]
+//	@Security	OAuth2AccessCode [read]
+//
+// Handles cross-check of variants API endpoint
+func Crosscheck(w http.ResponseWriter, r *http.Request) {
+
+	var rc int
+	var errMsg string
+
+	// get logger and config
+	logger := middleware.GetLogger(r)
+	cfg, ok := middleware.GetConfig(r)
+
+	// unable to get config
+	rc = http.StatusInternalServerError
+	errMsg = "configuration error: index: unable to get config"
+	if weberrors.HandleError(w, logger, weberrors.OkToError(ok), rc, errMsg) != nil {
+		return
+	}
+
+	// prepare response headers, always JSON
+	w.Header().Set("Content-Type", "application/json")
+
+	// get part and day from request URL
+	day := r.PathValue("day")
+	part := r.PathValue("part")
+	part_converted, err := strconv.Atoi(part)
+
+	rc = http.StatusBadRequest
+	errMsg = fmt.Sprintf("Solver for day %s part %s not implemented: part is not numerical", day, part)
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// read request
+	// limit the size of read response
+	r.Body = http.MaxBytesReader(w, r.Body, 1024*1024)
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// unmarshall request body
+	var p SolveRequest
+	err = json.Unmarshal(body, &p)
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body: Invalid JSON"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// decode the base64 encoded request
+	decoded_body, err := base64.StdEncoding.DecodeString(string(p.Input))
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body: Invalid Base64 encoding"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// parameters are validated before the solver is initialized,
+	// unknown day is reported by the solver
+	var params solver.Params
+
+	if item, ok := solver.Lookup(day); ok {
+		params, err = solverParams(r, item.Metadata, p.Params)
+	}
+
+	rc = http.StatusBadRequest
+	errMsg = fmt.Sprintf("Invalid parameters for day %s: %v", day, err)
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// cancel request after deadline
+	ctx, cancel := context.WithTimeout(r.Context(), cfg.SolverTimeout)
+	defer cancel()
+	ctx = solver.WithParams(ctx, params)
+
+	// init once, solve the part with every variant
+	c, err := solver.CrosscheckPart(ctx, day, part_converted, strings.NewReader(string(decoded_body)))
+
+	// unknown day or variants, initialization took too long, overloaded, solver panicked or input error?
+	switch {
+	case errors.Is(err, solver.ErrUnknownSolver):
+		rc = http.StatusNotFound
+		errMsg = fmt.Sprintf("Solver for day %s not implemented: day not implemented", day)
+	case errors.Is(err, solver.ErrUnknownVariant):
+		rc = http.StatusNotFound
+		errMsg = fmt.Sprintf("Solver for day %s part %s not implemented: part has no variants", day, part)
+	case errors.Is(err, solver.ErrTimeout):
+		rc = http.StatusGatewayTimeout
+		errMsg = fmt.Sprintf("Unable to intialize Solver for day %s", day)
+	case errors.Is(err, solver.ErrOverloaded):
+		rc = http.StatusServiceUnavailable
+		errMsg = fmt.Sprintf("Unable to intialize Solver for day %s", day)
+	case errors.Is(err, solver.ErrSolverPanic):
+		rc = http.StatusInternalServerError
+		errMsg = fmt.Sprintf("Unable to intialize Solver for day %s", day)
+	default:
+		rc = http.StatusBadRequest
+		errMsg = fmt.Sprintf("Unable to intialize Solver for day %s", day)
+	}
+
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// prepare response
+	response := CrosscheckResult{
+		Part:     c.Part,
+		Agree:    c.Agree(),
+		Disagree: c.Disagree,
+		Failed:   c.Failed,
+		Results:  make([]VariantResult, 0, len(c.Results)),
+	}
+
+	reference, _ := c.Reference()
+	first := reference.SolveTime
+
+	for _, result := range c.Results {
+		variantResult := VariantResult{PartResult: PartResult{SolveResult: newSolveResult(result.Result)}}
+
+		if first > 0 {
+			variantResult.TimeRatio = float64(result.SolveTime) / float64(first)
+		}
+
+		if result.Err != nil {
+			logger.Printf("Unable to solve for day %s part %d variant %s: %v", day, c.Part, result.Variant, result.Err)
+			variantResult.Error = result.Err.Error()
+			variantResult.ErrorKind = weberrors.Kind(result.Err)
+		} else {
+			metrics.RecordSolve(day, result.Result)
+		}
+
+		response.Results = append(response.Results, variantResult)
+	}
+
+	if len(c.Disagree) > 0 {
+		logger.Printf("Variants of day %s part %d disagree: %v", day, c.Part, c.Disagree)
+	}
+
+	b, err := json.Marshal(response)
+	rc = http.StatusInternalServerError
+	errMsg = fmt.Sprintf("unable to Marshal result: %s", err)
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// Steps godoc
+//
+//	@Summary		Solves the problem stepwise
+//	@Description	Provides states of stepwise solution for the day based on input
+//	@Description	Skips first start steps and returns at most count following steps
+//	@Description	Parameters declared in the metadata of the solver are given as query fields or params of the body
+//	@Tags			Private
+//	@Accepts		json
+//	@Produces		json
+//	@Security
+//	@Param		Authorization			header		string				true	"Bearer format, prefix with Bearer"
+//	@Param		day						path		string				true	"Day, format d[0-9]*"	example(d6)
+//	@Param		input					body		StepRequest			true	"Base64 encoded input and requested steps"
+//	@Success	200						{object}	StepResult			"Steps"
+//	@Failure	400						{object}	weberrors.AoCError	"Bad Request"
+//	@Failure	401						{object}	weberrors.AoCError	"Unathorized"
+//	@Failure	404						{object}	weberrors.AoCError	"Stepwise solver for the day not found"
+//	@Failure	429						{object}	weberrors.AoCError	"Request was Rate limited"
+//	@Failure	500						{object}	weberrors.AoCError	"Internal Server Error"
+//	@Failure	503						{object}	weberrors.AoCError	"Too many abandoned computations, retry later"
+//	@Failure	504						{object}	weberrors.AoCError	"Request took too long to compute"
+//	@Router		/solvers/{day}/steps	[post]
+//	@Security	OAuth2AccessCode [read]
+//
+// Handles stepwise solve API endpoint
+func Steps(w http.ResponseWriter, r *http.Request) {
+
+	var rc int
+	var errMsg string
+
+	// get logger and config
+	logger := middleware.GetLogger(r)
+	cfg, ok := middleware.GetConfig(r)
+
+	// unable to get config
+	rc = http.StatusInternalServerError
+	errMsg = "configuration error: index: unable to get config"
+	if weberrors.HandleError(w, logger, weberrors.OkToError(ok), rc, errMsg) != nil {
+		return
+	}
+
+	// prepare response headers, always JSON
+	w.Header().Set("Content-Type", "application/json")
+
+	// get day from request URL
+	day := r.PathValue("day")
+
+	// read request
+	// limit the size of read response
+	r.Body = http.MaxBytesReader(w, r.Body, 1024*1024)
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// unmarshall request body
+	var p StepRequest
+	err = json.Unmarshal(body, &p)
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body: Invalid JSON"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
 
-type InfoResponse struct {
-	Version        string `json:"version"`
-	Authentication string `json:"authentication"`
-} //@name InfoResponse
+	// validate requested steps
+	ok = p.Start >= 0 && p.Count > 0 && p.Count <= maxStepsPerRequest
 
-// Solve godoc
+	rc = http.StatusBadRequest
+	errMsg = fmt.Sprintf("invalid steps: start has to be positive, count between 1 and %d", maxStepsPerRequest)
+	if weberrors.HandleError(w, logger, weberrors.OkToError(ok), rc, errMsg) != nil {
+		return
+	}
+
+	// decode the base64 encoded request
+	decoded_body, err := base64.StdEncoding.DecodeString(string(p.Input))
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body: Invalid Base64 encoding"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// get solver
+	slvr, ok := solver.NewWithCtx(day)
+
+	rc = http.StatusNotFound
+	errMsg = fmt.Sprintf("Solver for day %s not implemented: day not implemented", day)
+	if weberrors.HandleError(w, logger, weberrors.OkToError(ok), rc, errMsg) != nil {
+		return
+	}
+
+	stepper, ok := slvr.(solver.StepperWithCtx)
+
+	rc = http.StatusNotFound
+	errMsg = fmt.Sprintf("Stepwise solver for day %s not implemented", day)
+	if weberrors.HandleError(w, logger, weberrors.OkToError(ok), rc, errMsg) != nil {
+		return
+	}
+
+	// parameters are validated before the solver is initialized
+	item, _ := solver.Lookup(day)
+	params, err := solverParams(r, item.Metadata, p.Params)
+
+	rc = http.StatusBadRequest
+	errMsg = fmt.Sprintf("Invalid parameters for day %s: %v", day, err)
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// cancel request after deadline
+	ctx, cancel := context.WithTimeout(r.Context(), cfg.SolverTimeout)
+	defer cancel()
+	ctx = solver.WithParams(ctx, params)
+
+	// init
+	err = stepper.InitCtx(ctx, strings.NewReader(string(decoded_body)))
+
+	// initialization took too long, overloaded, solver panicked or input error?
+	switch {
+	case errors.Is(err, solver.ErrTimeout):
+		rc = http.StatusGatewayTimeout
+	case errors.Is(err, solver.ErrOverloaded):
+		rc = http.StatusServiceUnavailable
+	case errors.Is(err, solver.ErrSolverPanic):
+		rc = http.StatusInternalServerError
+	default:
+		rc = http.StatusBadRequest
+	}
+
+	errMsg = fmt.Sprintf("Unable to intialize Solver for day %s", day)
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// skip first steps, collect the requested ones
+	result := StepResult{Steps: make([]json.RawMessage, 0, p.Count)}
+
+	for i := 0; i < p.Start+p.Count; i++ {
+		var state string
+		state, err = stepper.Next(ctx)
+
+		if errors.Is(err, solver.ErrNoMoreSteps) {
+			result.Done = true
+			err = nil
+			break
+		}
+
+		if err != nil {
+			break
+		}
+
+		if i >= p.Start {
+			result.Steps = append(result.Steps, toRawJSON(state))
+		}
+	}
+
+	// stepping took too long, overloaded or solver error?
+	switch {
+	case errors.Is(err, solver.ErrTimeout):
+		rc = http.StatusGatewayTimeout
+	case errors.Is(err, solver.ErrOverloaded):
+		rc = http.StatusServiceUnavailable
+	default:
+		rc = http.StatusInternalServerError
+	}
+
+	errMsg = fmt.Sprintf("Unable to solve stepwise for day %s", day)
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// prepare response
+	b, err := json.Marshal(result)
+	rc = http.StatusInternalServerError
+	errMsg = fmt.Sprintf("unable to Marshal result: %s", err)
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// Validate godoc
 //
-//	@Summary		Solves the problem
-//	@Description	Provides solution for the day and part based on input
+//	@Summary		Validates the input
+//	@Description	Parses the input without solving any part
+//	@Description	Invalid input is reported in the response with the positions of the errors,
+//	@Description	valid input is summarised by facts of solvers describing their input
+//	@Description	Parameters declared in the metadata of the solver are given as query fields or params of the body
 //	@Tags			Private
 //	@Accepts		json
 //	@Produces		json
 //	@Security
 //	@Param		Authorization			header		string				true	"Bearer format, prefix with Bearer"
 //	@Param		day						path		string				true	"Day, format d[0-9]*"	example(d1)
-//	@Param		part					path		int					true	"Problem part"			example(1)
-//	@Param		input					body		SolveRequest		true	"Solve Base64 encoded input"
-//	@Success	200						{object}	SolveResult			"Result"
+//	@Param		input					body		SolveRequest		true	"Base64 encoded input"
+//	@Success	200						{object}	ValidateResult		"Validation"
 //	@Failure	400						{object}	weberrors.AoCError	"Bad Request"
 //	@Failure	401						{object}	weberrors.AoCError	"Unathorized"
 //	@Failure	404						{object}	weberrors.AoCError	"Solver for the day not found"
 //	@Failure	429						{object}	weberrors.AoCError	"Request was Rate limited"
 //	@Failure	500						{object}	weberrors.AoCError	"Internal Server Error"
+//	@Failure	503						{object}	weberrors.AoCError	"Too many abandoned computations, retry later"
 //	@Failure	504						{object}	weberrors.AoCError	"Request took too long to compute"
-//	@Router		/solvers/{day}/{part}	[post]
+//	@Router		/solvers/{day}/validate	[post]
 //	@Security	OAuth2AccessCode [read]
 //
-// Handles solve API endpoint
-func Solve(w http.ResponseWriter, r *http.Request) {
+// Handles input validation API endpoint
+func Validate(w http.ResponseWriter, r *http.Request) {
 
 	var rc int
 	var errMsg string
@@ -84,16 +892,8 @@ func Solve(w http.ResponseWriter, r *http.Request) {
 	// prepare response headers, always JSON
 	w.Header().Set("Content-Type", "application/json")
 
-	// get part and day from request URL
+	// get day from request URL
 	day := r.PathValue("day")
-	part := r.PathValue("part")
-	part_converted, err := strconv.Atoi(part)
-
-	rc = http.StatusBadRequest
-	errMsg = fmt.Sprintf("Solver for day %s part %s not implemented: part is not numerical", day, part)
-	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
-		return
-	}
 
 	// read request
 	// limit the size of read response
@@ -127,51 +927,164 @@ func Solve(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// get solver
-	slvr, ok := solver.NewWithCtx(day)
+	// parameters are validated before the solver is initialized,
+	// unknown day is reported by the solver
+	var params solver.Params
 
-	rc = http.StatusNotFound
-	errMsg = fmt.Sprintf("Solver for day %s part %s not implemented: day not implemented", day, part)
-	if weberrors.HandleError(w, logger, weberrors.OkToError(ok), rc, errMsg) != nil {
+	if item, ok := solver.Lookup(day); ok {
+		params, err = solverParams(r, item.Metadata, p.Params)
+	}
+
+	rc = http.StatusBadRequest
+	errMsg = fmt.Sprintf("Invalid parameters for day %s: %v", day, err)
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
 		return
 	}
 
 	// cancel request after deadline
 	ctx, cancel := context.WithTimeout(r.Context(), cfg.SolverTimeout)
 	defer cancel()
+	ctx = solver.WithParams(ctx, params)
 
-	// init
-	err = slvr.InitCtx(ctx, strings.NewReader(string(decoded_body)))
+	// init only, input errors are part of the response
+	v, err := solver.Validate(ctx, day, strings.NewReader(string(decoded_body)))
 
-	// initialization took too long or input error?
-	if errors.Is(err, solver.ErrTimeout) {
+	// unknown day, initialization took too long, overloaded or failed?
+	switch {
+	case errors.Is(err, solver.ErrUnknownSolver):
+		rc = http.StatusNotFound
+		errMsg = fmt.Sprintf("Solver for day %s not implemented: day not implemented", day)
+	case errors.Is(err, solver.ErrTimeout):
 		rc = http.StatusGatewayTimeout
-	} else {
-		rc = http.StatusBadRequest
+		errMsg = fmt.Sprintf("Unable to intialize Solver for day %s", day)
+	case errors.Is(err, solver.ErrOverloaded):
+		rc = http.StatusServiceUnavailable
+		errMsg = fmt.Sprintf("Unable to intialize Solver for day %s", day)
+	default:
+		rc = http.StatusInternalServerError
+		errMsg = fmt.Sprintf("Unable to intialize Solver for day %s", day)
 	}
 
-	errMsg = fmt.Sprintf("Unable to intialize Solver for day %s", day)
 	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
 		return
 	}
 
-	// try to solve
-	result, err := slvr.SolveCtx(ctx, part_converted)
+	// prepare response
+	result := ValidateResult{
+		Valid:     v.Valid(),
+		Facts:     v.Facts,
+		ParseTime: float64(v.ParseTime.Microseconds()) / 1000,
+	}
 
-	// solution took too long or solver error?
-	if errors.Is(err, solver.ErrTimeout) {
-		rc = http.StatusGatewayTimeout
-	} else {
-		rc = http.StatusInternalServerError
+	if v.Err != nil {
+		result.Message = v.Err.Error()
+		result.Details = weberrors.Details(v.Err)
 	}
 
-	errMsg = fmt.Sprintf("Unable to solve for day %s part %s", day, part)
+	b, err := json.Marshal(result)
+	rc = http.StatusInternalServerError
+	errMsg = fmt.Sprintf("unable to Marshal result: %s", err)
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// Detect godoc
+//
+//	@Summary		Detects the day of the input
+//	@Description	Parses the input by every solver and scores how well the input fits the puzzle
+//	@Description	Days failing to parse the input are not listed
+//	@Tags			Private
+//	@Accepts		json
+//	@Produces		json
+//	@Security
+//	@Param		Authorization			header		string				true	"Bearer format, prefix with Bearer"
+//	@Param		input					body		SolveRequest		true	"Base64 encoded input"
+//	@Success	200						{object}	DetectResult		"Candidates"
+//	@Failure	400						{object}	weberrors.AoCError	"Bad Request"
+//	@Failure	401						{object}	weberrors.AoCError	"Unathorized"
+//	@Failure	429						{object}	weberrors.AoCError	"Request was Rate limited"
+//	@Failure	500						{object}	weberrors.AoCError	"Internal Server Error"
+//	@Failure	504						{object}	weberrors.AoCError	"Request took too long to compute"
+//	@Router		/detect	[post]
+//	@Security	OAuth2AccessCode [read]
+//
+// Handles day detection API endpoint
+func Detect(w http.ResponseWriter, r *http.Request) {
+
+	var rc int
+	var errMsg string
+
+	// get logger and config
+	logger := middleware.GetLogger(r)
+	cfg, ok := middleware.GetConfig(r)
+
+	// unable to get config
+	rc = http.StatusInternalServerError
+	errMsg = "configuration error: index: unable to get config"
+	if weberrors.HandleError(w, logger, weberrors.OkToError(ok), rc, errMsg) != nil {
+		return
+	}
+
+	// prepare response headers, always JSON
+	w.Header().Set("Content-Type", "application/json")
+
+	// read request
+	// limit the size of read response
+	r.Body = http.MaxBytesReader(w, r.Body, 1024*1024)
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// unmarshall request body
+	var p SolveRequest
+	err = json.Unmarshal(body, &p)
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body: Invalid JSON"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// decode the base64 encoded request
+	decoded_body, err := base64.StdEncoding.DecodeString(string(p.Input))
+
+	rc = http.StatusBadRequest
+	errMsg = "unable to read body: Invalid Base64 encoding"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	// cancel request after deadline
+	ctx, cancel := context.WithTimeout(r.Context(), cfg.SolverTimeout)
+	defer cancel()
+
+	// parse by every solver, each under short deadline
+	candidates, err := solver.Detect(ctx, strings.NewReader(string(decoded_body)), min(detectTimeout, cfg.SolverTimeout))
+
+	rc = http.StatusGatewayTimeout
+	errMsg = "Unable to detect day of the input"
 	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
 		return
 	}
 
 	// prepare response
-	b, err := json.Marshal(SolveResult{Output: result})
+	result := DetectResult{Candidates: make([]DetectCandidate, 0, len(candidates))}
+
+	for _, c := range candidates {
+		item, _ := solver.Lookup(c.Name)
+		result.Candidates = append(result.Candidates, DetectCandidate{Day: c.Name, Title: item.Metadata.Title, Score: c.Score})
+	}
+
+	b, err := json.Marshal(result)
 	rc = http.StatusInternalServerError
 	errMsg = fmt.Sprintf("unable to Marshal result: %s", err)
 	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
@@ -181,6 +1094,66 @@ func Solve(w http.ResponseWriter, r *http.Request) {
 	w.Write(b)
 }
 
+// Converts result of the solver into API response
+func newSolveResult(r solver.Result) SolveResult {
+	result := SolveResult{
+		Output:      r.Output,
+		Part:        r.Part,
+		Variant:     r.Variant,
+		ParseTime:   float64(r.ParseTime.Microseconds()) / 1000,
+		SolveTime:   float64(r.SolveTime.Microseconds()) / 1000,
+		CPUTime:     float64(r.CPUTime.Microseconds()) / 1000,
+		Allocated:   r.Allocated,
+		ProcessWide: r.ProcessWide,
+		Stats:       r.Stats,
+	}
+
+	if r.Value != nil {
+		result.Value = r.Value
+		result.ValueType = solver.ValueType(r.Value)
+	}
+
+	return result
+}
+
+// Returns parameters of the solver given as query fields or params of
+// the body, validated against the parameters declared in metadata
+// Query fields which are not parameters of the solver are ignored
+func solverParams(r *http.Request, m solver.Metadata, body map[string]json.RawMessage) (solver.Params, error) {
+	values := make(map[string]string)
+	query := r.URL.Query()
+
+	for _, p := range m.Params {
+		if query.Has(p.Name) {
+			values[p.Name] = query.Get(p.Name)
+		}
+	}
+
+	for name, raw := range body {
+		var text string
+
+		if err := json.Unmarshal(raw, &text); err != nil {
+			text = string(raw)
+		}
+
+		values[name] = text
+	}
+
+	return m.ParseParams(values)
+}
+
+// Converts state returned by stepper into JSON
+// States which are not valid JSON are encoded as JSON strings
+func toRawJSON(state string) json.RawMessage {
+	if json.Valid([]byte(state)) {
+		return json.RawMessage(state)
+	}
+
+	b, _ := json.Marshal(state)
+
+	return json.RawMessage(b)
+}
+
 // SolverListing godoc
 //
 //	@Summary		Solve List
@@ -410,6 +1383,38 @@ func exchangeCodeForToken(provider *config.OAuthProvider, code string) (middlewa
 	}
 }
 
+// Metrics godoc
+//
+//	@Summary		Metrics of the solvers
+//	@Description	Provides counters of solves, panics and the solver supervisor, keyed by day and part
+//	@Tags			Private
+//	@Produces		json
+//	@Security
+//	@Param		Authorization	header		string				true	"Bearer format, prefix with Bearer"
+//	@Success	200				{object}	map[string]any		"Metrics by name"
+//	@Failure	401				{object}	weberrors.AoCError	"Unathorized"
+//	@Failure	429				{object}	weberrors.AoCError	"Request was Rate limited"
+//	@Failure	500				{object}	weberrors.AoCError	"Internal Server Error"
+//	@Router		/metrics	[GET]
+//	@Security	OAuth2AccessCode [read]
+//
+// Handles metrics endpoint
+func Metrics(w http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r)
+
+	// prepare response body
+	b, err := json.Marshal(metrics.Snapshot())
+
+	rc := http.StatusInternalServerError
+	errMsg := "Unable to marshal metrics"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
 // Info godoc
 //
 //	@Summary		Information about the backend
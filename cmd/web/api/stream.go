@@ -0,0 +1,142 @@
+// Streaming of solver progress
+package api
+
+import (
+	"advent2024/pkg/solver"
+	"advent2024/web/weberrors"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Media type of streamed responses, one JSON document per line
+const ndjsonContentType = "application/x-ndjson"
+
+// Line of streamed API response
+// Progress lines are followed by exactly one line with result or error
+type StreamLine struct {
+	Progress *solver.Progress    `json:"progress,omitempty"`
+	Result   any                 `json:"result,omitempty" swaggertype:"object"`
+	Error    *weberrors.AoCError `json:"error,omitempty"`
+} //@name StreamLine
+
+// Progress stream of one request
+// Response is sent as a plain JSON document until the first progress
+// is reported, afterwards everything is sent as lines of the stream
+type progressStream struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	started bool
+	closed  bool
+	percent map[int]int
+}
+
+// Creates progress stream if the client accepts streamed responses
+// Returns nil otherwise, nil stream is valid and never streams
+func newProgressStream(w http.ResponseWriter, r *http.Request) *progressStream {
+	if !strings.Contains(r.Header.Get("Accept"), ndjsonContentType) {
+		return nil
+	}
+
+	return &progressStream{
+		w:       w,
+		rc:      http.NewResponseController(w),
+		percent: make(map[int]int),
+	}
+}
+
+// Sends the progress as a line of the stream
+// Only changes of whole percents are sent
+func (s *progressStream) Progress(p solver.Progress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || p.Total <= 0 {
+		return
+	}
+
+	percent := p.Done * 100 / p.Total
+
+	if last, ok := s.percent[p.Part]; ok && last == percent {
+		return
+	}
+
+	s.percent[p.Part] = percent
+
+	if !s.started {
+		s.w.Header().Set("Content-Type", ndjsonContentType)
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+
+	s.writeLine(StreamLine{Progress: &p})
+}
+
+// Stops sending progress
+// Returns true if the stream was started
+func (s *progressStream) stop() bool {
+	if s == nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+
+	return s.started
+}
+
+// Handles errors, as weberrors.HandleError
+// Once the stream is started, the error is sent as the last line
+func (s *progressStream) HandleError(w http.ResponseWriter, logger *log.Logger, err error, httpErrorCode int, errMsg string) error {
+	if err == nil {
+		return nil
+	}
+
+	if !s.stop() {
+		return weberrors.HandleError(w, logger, err, httpErrorCode, errMsg)
+	}
+
+	logger.Println(errMsg)
+
+	aocErr := weberrors.NewErrorWithDetails(httpErrorCode, errMsg, err)
+	s.writeLine(StreamLine{Error: &aocErr})
+
+	return err
+}
+
+// Sends the response
+// Once the stream is started, the response is sent as the last line
+func (s *progressStream) Respond(w http.ResponseWriter, logger *log.Logger, v any) {
+	if s.stop() {
+		s.writeLine(StreamLine{Result: v})
+		return
+	}
+
+	b, err := json.Marshal(v)
+
+	rc := http.StatusInternalServerError
+	errMsg := "unable to Marshal result"
+	if weberrors.HandleError(w, logger, err, rc, errMsg) != nil {
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// Writes line of the stream and flushes it to the client
+func (s *progressStream) writeLine(line StreamLine) {
+	b, err := json.Marshal(line)
+
+	if err != nil {
+		return
+	}
+
+	s.w.Write(append(b, '\n'))
+	s.rc.Flush()
+}
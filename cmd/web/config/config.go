@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"runtime"
 	"strconv"
 	"time"
 )
@@ -27,6 +28,12 @@ type Config struct {
 	APIRate          int
 	APIBurst         int
 	SolverTimeout    time.Duration
+	MaxAbandoned     int
+	Isolate          bool
+	WorkerMemory     int
+	SolverDir        string
+	WasmDir          string
+	WasmMemoryLimit  int
 	OAuth            bool
 	JWTSecret        string
 	JWTTokenValidity time.Duration
@@ -64,6 +71,10 @@ func NewConfig() Config {
 		APIRate:          3,
 		APIBurst:         3,
 		SolverTimeout:    time.Duration(5 * time.Second),
+		MaxAbandoned:     runtime.NumCPU(),
+		Isolate:          false,
+		WorkerMemory:     1024,
+		WasmMemoryLimit:  256,
 		JWTTokenValidity: time.Duration(900 * time.Second),
 		OAuthProviders:   make(map[string]OAuthProvider),
 	}
@@ -130,6 +141,15 @@ func LoadConfig() (Config, []error) {
 	defVal := int(config.SolverTimeout.Seconds())
 	solverTimeout := flag.String("solver-timeout", envOrDefault("API_SOLVER_TIMEOUT", strconv.Itoa(defVal)), "Solver timeout in seconds")
 
+	maxAbandoned := flag.String("max-abandoned", envOrDefault("API_MAX_ABANDONED", strconv.Itoa(config.MaxAbandoned)), "Limit of timed out solvers still running, further solves are refused, 0 is unlimited")
+
+	isolate := flag.String("isolate", envOrDefault("SOLVER_ISOLATE", fmt.Sprintf("%t", config.Isolate)), "Solve in child processes with resource limits")
+	workerMemory := flag.String("worker-memory-limit", envOrDefault("WORKER_MEMORY_LIMIT", strconv.Itoa(config.WorkerMemory)), "Address space limit of isolated solves in MiB, 0 is unlimited")
+
+	solverDir := flag.String("solver-dir", envOrDefault("SOLVER_DIR", ""), "Directory with external solver executables")
+	wasmDir := flag.String("wasm-dir", envOrDefault("WASM_DIR", ""), "Directory with sandboxed .wasm solver modules")
+	wasmMemoryLimit := flag.String("wasm-memory-limit", envOrDefault("WASM_MEMORY_LIMIT", strconv.Itoa(config.WasmMemoryLimit)), "Memory limit of sandboxed solvers in MiB")
+
 	oAuth := flag.String("oauth", envOrDefault("ENABLE_OAUTH", fmt.Sprintf("%t", config.OAuth)), "Enables OAuth API authentication, requires jwt secret and per provider information")
 
 	jwtSecret := flag.String("jwt-secret", envOrDefault("JWT_SECRET", ""), "JWT Secret")
@@ -159,6 +179,9 @@ func LoadConfig() (Config, []error) {
 	parseInt("port", *port, &config.Port)
 	parseInt("apiRate", *apiRate, &config.APIRate)
 	parseInt("apiBurst", *apiBurst, &config.APIBurst)
+	parseInt("wasmMemoryLimit", *wasmMemoryLimit, &config.WasmMemoryLimit)
+	parseInt("maxAbandoned", *maxAbandoned, &config.MaxAbandoned)
+	parseInt("workerMemory", *workerMemory, &config.WorkerMemory)
 
 	// parse durations
 	var durationInt int
@@ -168,11 +191,19 @@ func LoadConfig() (Config, []error) {
 	parseInt("solverTimeout", *solverTimeout, &durationInt)
 	config.SolverTimeout = time.Duration(time.Duration(durationInt) * time.Second)
 
+	config.SolverDir = *solverDir
+	config.WasmDir = *wasmDir
+
 	// parse API Only
 	if *apiOnly == "true" {
 		config.APIOnly = true
 	}
 
+	// parse isolation
+	if *isolate == "true" {
+		config.Isolate = true
+	}
+
 	// parse https
 	if *enableHttps == "true" {
 		config.EnableTLS = true
@@ -217,6 +248,22 @@ func (cfg *Config) ValidateConfig() (bool, []error) {
 		errs = append(errs, fmt.Errorf("port %d outside of range 0 - 65535", cfg.Port))
 	}
 
+	if cfg.MaxAbandoned < 0 {
+		valid = false
+		errs = append(errs, fmt.Errorf("max abandoned %d can't be negative", cfg.MaxAbandoned))
+	}
+
+	if cfg.Isolate && cfg.WorkerMemory < 0 {
+		valid = false
+		errs = append(errs, fmt.Errorf("worker memory limit %d MiB can't be negative", cfg.WorkerMemory))
+	}
+
+	// sandbox needs at least one memory page
+	if cfg.WasmDir != "" && cfg.WasmMemoryLimit < 1 {
+		valid = false
+		errs = append(errs, fmt.Errorf("wasm memory limit %d MiB has to be positive", cfg.WasmMemoryLimit))
+	}
+
 	// if TLS is enabled both cert and key has to be provided
 	if cfg.EnableTLS {
 		// validation breaking errors
@@ -21,6 +21,130 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/detect": {
+            "post": {
+                "security": [
+                    {
+                        "OAuth2AccessCode ": [
+                            "read"
+                        ]
+                    }
+                ],
+                "description": "Parses the input by every solver and scores how well the input fits the puzzle\nDays failing to parse the input are not listed",
+                "tags": [
+                    "Private"
+                ],
+                "summary": "Detects the day of the input",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer format, prefix with Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Base64 encoded input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Candidates",
+                        "schema": {
+                            "$ref": "#/definitions/DetectResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unathorized",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Request was Rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "504": {
+                        "description": "Request took too long to compute",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "security": [
+                    {
+                        "OAuth2AccessCode ": [
+                            "read"
+                        ]
+                    }
+                ],
+                "description": "Provides counters of solves, panics and the solver supervisor, keyed by day and part",
+                "tags": [
+                    "Private"
+                ],
+                "summary": "Metrics of the solvers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer format, prefix with Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metrics by name",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unathorized",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Request was Rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
         "/public/auth_token": {
             "post": {
                 "description": "Exchanges OAuth code for a JWT token",
@@ -150,7 +274,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/solvers/{day}/{part}": {
+        "/solvers/{day}": {
             "post": {
                 "security": [
                     {
@@ -159,11 +283,11 @@ const docTemplate = `{
                         ]
                     }
                 ],
-                "description": "Provides solution for the day and part based on input",
+                "description": "Provides solutions for all parts of the day based on input\nInput is parsed once, parts are solved concurrently under one deadline\nParts which failed carry the error in the result\nWith Accept application/x-ndjson progress is streamed as lines of StreamLine,\nthe last line carries the results or the error\nParameters declared in the metadata of the solver are given as query fields or params of the body",
                 "tags": [
                     "Private"
                 ],
-                "summary": "Solves the problem",
+                "summary": "Solves all parts of the problem",
                 "parameters": [
                     {
                         "type": "string",
@@ -172,6 +296,12 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "application/x-ndjson to stream progress",
+                        "name": "Accept",
+                        "in": "header"
+                    },
                     {
                         "type": "string",
                         "example": "d1",
@@ -180,14 +310,6 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
-                    {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "Problem part",
-                        "name": "part",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "Solve Base64 encoded input",
                         "name": "input",
@@ -200,9 +322,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Result",
+                        "description": "Results",
                         "schema": {
-                            "$ref": "#/definitions/Response"
+                            "$ref": "#/definitions/SolveAllResponse"
                         }
                     },
                     "400": {
@@ -235,6 +357,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/Error"
                         }
                     },
+                    "503": {
+                        "description": "Too many abandoned computations, retry later",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
                     "504": {
                         "description": "Request took too long to compute",
                         "schema": {
@@ -243,68 +371,825 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "CodeExchangeRequest": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string"
-                },
-                "provider": {
-                    "type": "string"
-                }
-            }
-        },
-        "Error": {
-            "type": "object",
-            "properties": {
-                "errorcode": {
-                    "type": "integer"
-                },
-                "errormessage": {
-                    "type": "string"
-                }
-            }
-        },
-        "InfoResponse": {
-            "type": "object",
-            "properties": {
-                "authentication": {
-                    "type": "string"
-                },
-                "version": {
-                    "type": "string"
-                }
-            }
         },
-        "RegistryItem": {
-            "type": "object",
-            "properties": {
-                "name": {
-                    "type": "string"
-                },
-                "next": {
-                    "type": "boolean"
+        "/solvers/{day}/steps": {
+            "post": {
+                "security": [
+                    {
+                        "OAuth2AccessCode ": [
+                            "read"
+                        ]
+                    }
+                ],
+                "description": "Provides states of stepwise solution for the day based on input\nSkips first start steps and returns at most count following steps\nParameters declared in the metadata of the solver are given as query fields or params of the body",
+                "tags": [
+                    "Private"
+                ],
+                "summary": "Solves the problem stepwise",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer format, prefix with Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "d6",
+                        "description": "Day, format d[0-9]*",
+                        "name": "day",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Base64 encoded input and requested steps",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/StepRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Steps",
+                        "schema": {
+                            "$ref": "#/definitions/StepResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unathorized",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Stepwise solver for the day not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Request was Rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Too many abandoned computations, retry later",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "504": {
+                        "description": "Request took too long to compute",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
                 }
             }
         },
-        "Request": {
-            "type": "object",
-            "properties": {
-                "input": {
+        "/solvers/{day}/validate": {
+            "post": {
+                "security": [
+                    {
+                        "OAuth2AccessCode ": [
+                            "read"
+                        ]
+                    }
+                ],
+                "description": "Parses the input without solving any part\nInvalid input is reported in the response with the positions of the errors,\nvalid input is summarised by facts of solvers describing their input\nParameters declared in the metadata of the solver are given as query fields or params of the body",
+                "tags": [
+                    "Private"
+                ],
+                "summary": "Validates the input",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer format, prefix with Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "d1",
+                        "description": "Day, format d[0-9]*",
+                        "name": "day",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Base64 encoded input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Validation",
+                        "schema": {
+                            "$ref": "#/definitions/ValidateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unathorized",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Solver for the day not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Request was Rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Too many abandoned computations, retry later",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "504": {
+                        "description": "Request took too long to compute",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/solvers/{day}/{part}": {
+            "post": {
+                "security": [
+                    {
+                        "OAuth2AccessCode ": [
+                            "read"
+                        ]
+                    }
+                ],
+                "description": "Provides solution for the day and part based on input\nWith Accept application/x-ndjson progress is streamed as lines of StreamLine,\nthe last line carries the result or the error\nParameters declared in the metadata of the solver are given as query fields or params of the body",
+                "tags": [
+                    "Private"
+                ],
+                "summary": "Solves the problem",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer format, prefix with Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "application/x-ndjson to stream progress",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "example": "d1",
+                        "description": "Day, format d[0-9]*",
+                        "name": "day",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Problem part",
+                        "name": "part",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "parallel",
+                        "description": "Variant of the part, default implementation if empty",
+                        "name": "variant",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Solve Base64 encoded input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Result",
+                        "schema": {
+                            "$ref": "#/definitions/Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unathorized",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Solver for the day, part or variant not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Request was Rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Too many abandoned computations, retry later",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "504": {
+                        "description": "Request took too long to compute",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/solvers/{day}/{part}/crosscheck": {
+            "post": {
+                "security": [
+                    {
+                        "OAuth2AccessCode ": [
+                            "read"
+                        ]
+                    }
+                ],
+                "description": "Solves the part with every variant on the same input, one after another\nReports variants which fail or disagree with the first successful one and their solve times\nParameters declared in the metadata of the solver are given as query fields or params of the body",
+                "tags": [
+                    "Private"
+                ],
+                "summary": "Cross-checks variants of the part",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer format, prefix with Bearer",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "d8",
+                        "description": "Day, format d[0-9]*",
+                        "name": "day",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 2,
+                        "description": "Problem part",
+                        "name": "part",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Solve Base64 encoded input",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Results of the variants",
+                        "schema": {
+                            "$ref": "#/definitions/CrosscheckResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unathorized",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Solver for the day or variants of the part not found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Request was Rate limited",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Too many abandoned computations, retry later",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "504": {
+                        "description": "Request took too long to compute",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "Candidate": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "string",
+                    "example": "d6"
+                },
+                "score": {
+                    "type": "number",
+                    "example": 1
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Guard Gallivant"
+                }
+            }
+        },
+        "CodeExchangeRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                }
+            }
+        },
+        "CrosscheckResponse": {
+            "type": "object",
+            "properties": {
+                "agree": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "disagree": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "failed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "part": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/VariantResponse"
+                    }
+                }
+            }
+        },
+        "DetectResponse": {
+            "type": "object",
+            "properties": {
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Candidate"
+                    }
+                }
+            }
+        },
+        "Error": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ErrorDetail"
+                    }
+                },
+                "errorcode": {
+                    "type": "integer"
+                },
+                "errormessage": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "invalid_input",
+                        "timeout",
+                        "overloaded",
+                        "unknown_part",
+                        "unknown_variant",
+                        "invalid_param",
+                        "solver_panic"
+                    ]
+                }
+            }
+        },
+        "ErrorDetail": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "expected": {
+                    "type": "string",
+                    "example": "number"
+                },
+                "found": {
+                    "type": "string",
+                    "example": "x1"
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "message": {
+                    "type": "string",
+                    "example": "not a number"
+                }
+            }
+        },
+        "InfoResponse": {
+            "type": "object",
+            "properties": {
+                "authentication": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "Param": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Blinks of every part"
+                },
+                "max": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "min": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "name": {
+                    "type": "string",
+                    "example": "blinks"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "int",
+                        "string"
+                    ],
+                    "example": "int"
+                }
+            }
+        },
+        "Part": {
+            "type": "object",
+            "properties": {
+                "exampleAnswer": {
+                    "type": "string",
+                    "example": "11"
+                },
+                "label": {
+                    "type": "string",
+                    "example": "Total distance"
+                },
+                "part": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "sequential",
+                        "parallel"
+                    ]
+                }
+            }
+        },
+        "PartResponse": {
+            "type": "object",
+            "properties": {
+                "allocatedBytes": {
+                    "type": "integer",
+                    "example": 40960
+                },
+                "cpuTimeMs": {
+                    "type": "number",
+                    "example": 1.2
+                },
+                "error": {
+                    "type": "string"
+                },
+                "errorKind": {
+                    "type": "string",
+                    "enum": [
+                        "invalid_input",
+                        "timeout",
+                        "overloaded",
+                        "unknown_part",
+                        "unknown_variant",
+                        "invalid_param",
+                        "solver_panic"
+                    ]
+                },
+                "output": {
+                    "type": "string",
+                    "example": "11"
+                },
+                "parseTimeMs": {
+                    "type": "number",
+                    "example": 0.25
+                },
+                "part": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "processWideUsage": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "solveTimeMs": {
+                    "type": "number",
+                    "example": 1.5
+                },
+                "stats": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "value": {
+                    "type": "integer",
+                    "example": 11
+                },
+                "valueType": {
+                    "type": "string",
+                    "enum": [
+                        "int",
+                        "bigint",
+                        "string"
+                    ],
+                    "example": "int"
+                },
+                "variant": {
+                    "type": "string",
+                    "example": "parallel"
+                }
+            }
+        },
+        "RegistryItem": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "example": {
+                    "type": "string"
+                },
+                "inputFormat": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next": {
+                    "type": "boolean"
+                },
+                "params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Param"
+                    }
+                },
+                "parts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Part"
+                    }
+                },
+                "progress": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Historian Hysteria"
+                }
+            }
+        },
+        "Request": {
+            "type": "object",
+            "properties": {
+                "input": {
                     "type": "string",
                     "format": "base64",
                     "example": "MyAgIDQKNCAgIDMKMiAgIDUKMSAgIDMKMyAgIDkKMyAgIDMK"
+                },
+                "params": {
+                    "type": "object"
                 }
             }
         },
         "Response": {
             "type": "object",
             "properties": {
+                "allocatedBytes": {
+                    "type": "integer",
+                    "example": 40960
+                },
+                "cpuTimeMs": {
+                    "type": "number",
+                    "example": 1.2
+                },
                 "output": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "11"
+                },
+                "parseTimeMs": {
+                    "type": "number",
+                    "example": 0.25
+                },
+                "part": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "processWideUsage": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "solveTimeMs": {
+                    "type": "number",
+                    "example": 1.5
+                },
+                "stats": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "value": {
+                    "type": "integer",
+                    "example": 11
+                },
+                "valueType": {
+                    "type": "string",
+                    "enum": [
+                        "int",
+                        "bigint",
+                        "string"
+                    ],
+                    "example": "int"
+                },
+                "variant": {
+                    "type": "string",
+                    "example": "parallel"
+                }
+            }
+        },
+        "SolveAllResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/PartResponse"
+                    }
+                }
+            }
+        },
+        "StepRequest": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "input": {
+                    "type": "string",
+                    "format": "base64",
+                    "example": "MTI1IDE3Cg=="
+                },
+                "params": {
+                    "type": "object"
+                },
+                "start": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "StepResponse": {
+            "type": "object",
+            "properties": {
+                "done": {
+                    "type": "boolean"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
                 }
             }
         },
@@ -315,6 +1200,112 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "ValidateResponse": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ErrorDetail"
+                    }
+                },
+                "facts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "d1 line 2, column 3: not a number \"x4\": invalid input"
+                },
+                "parseTimeMs": {
+                    "type": "number",
+                    "example": 0.25
+                },
+                "valid": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "VariantResponse": {
+            "type": "object",
+            "properties": {
+                "allocatedBytes": {
+                    "type": "integer",
+                    "example": 40960
+                },
+                "cpuTimeMs": {
+                    "type": "number",
+                    "example": 1.2
+                },
+                "error": {
+                    "type": "string"
+                },
+                "errorKind": {
+                    "type": "string",
+                    "enum": [
+                        "invalid_input",
+                        "timeout",
+                        "overloaded",
+                        "unknown_part",
+                        "unknown_variant",
+                        "invalid_param",
+                        "solver_panic"
+                    ]
+                },
+                "output": {
+                    "type": "string",
+                    "example": "11"
+                },
+                "parseTimeMs": {
+                    "type": "number",
+                    "example": 0.25
+                },
+                "part": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "processWideUsage": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "solveTimeMs": {
+                    "type": "number",
+                    "example": 1.5
+                },
+                "stats": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "timeRatio": {
+                    "type": "number",
+                    "example": 0.4
+                },
+                "value": {
+                    "type": "integer",
+                    "example": 11
+                },
+                "valueType": {
+                    "type": "string",
+                    "enum": [
+                        "int",
+                        "bigint",
+                        "string"
+                    ],
+                    "example": "int"
+                },
+                "variant": {
+                    "type": "string",
+                    "example": "parallel"
+                }
+            }
         }
     },
     "securityDefinitions": {
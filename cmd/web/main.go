@@ -2,6 +2,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -19,6 +20,8 @@ import (
 	_ "advent2024/pkg/d7"
 	_ "advent2024/pkg/d8"
 	_ "advent2024/pkg/d9"
+	"advent2024/pkg/solver"
+	"advent2024/pkg/wasm"
 
 	_ "advent2024/web/docs"
 
@@ -26,8 +29,10 @@ import (
 
 	"advent2024/web/api"
 	"advent2024/web/config"
+	"advent2024/web/metrics"
 	"advent2024/web/middleware"
 	"advent2024/web/webhandlers"
+	"advent2024/web/worker"
 )
 
 var Version string = "dev"
@@ -52,6 +57,14 @@ var Version string = "dev"
 // @scope.read								Grants read access
 // @description							GitHub OAuth
 func main() {
+	// re-executed to solve in isolation, serves one solver
+	if worker.IsWorker(os.Args) {
+		if err := worker.Serve(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
 
 	// parse and validate config.
 
@@ -76,6 +89,53 @@ func main() {
 
 	cfg.Version = Version
 
+	// load external solvers, failing executables are skipped
+
+	if cfg.SolverDir != "" {
+		names, err := solver.LoadExternal(context.Background(), cfg.SolverDir)
+
+		if err != nil {
+			log.Println(err)
+		}
+
+		log.Printf("Loaded external solvers: %v\n", names)
+	}
+
+	// load sandboxed solvers, failing modules are skipped
+
+	if cfg.WasmDir != "" {
+		rt, err := wasm.NewRuntime(context.Background(), wasm.Config{
+			MemoryLimit: uint64(cfg.WasmMemoryLimit) * 1024 * 1024,
+			Timeout:     cfg.SolverTimeout,
+		})
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		names, err := rt.Load(context.Background(), cfg.WasmDir)
+
+		if err != nil {
+			log.Println(err)
+		}
+
+		log.Printf("Loaded sandboxed solvers: %v\n", names)
+	}
+
+	// solve built-in solvers in child processes with resource limits
+
+	if cfg.Isolate {
+		exe, err := os.Executable()
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		solver.Isolate(worker.Runner(exe, worker.Limits{Memory: cfg.WorkerMemory, CPU: cfg.SolverTimeout}))
+
+		log.Printf("Solving in child processes limited to %d MiB\n", cfg.WorkerMemory)
+	}
+
 	// parse templates
 
 	funcMap := template.FuncMap{
@@ -101,6 +161,16 @@ func main() {
 
 	logger := middleware.NewLogger(&cfg)
 
+	// count and log panics of the solvers
+
+	solver.OnPanic(func(p *solver.PanicError) {
+		metrics.CountPanic(p)
+		logger.Printf("%v\n%s", p, p.Stack)
+	})
+
+	// cap timed out solvers which are still running
+	solver.SetMaxAbandoned(cfg.MaxAbandoned)
+
 	// create http muxes
 	webMux := http.NewServeMux()
 	apiMux := http.NewServeMux()
@@ -137,7 +207,13 @@ func main() {
 
 	// api
 	apiMux.HandleFunc("GET /solvers", api.SolverListing)
+	apiMux.HandleFunc("POST /solvers/{day}", api.SolveAll)
 	apiMux.HandleFunc("POST /solvers/{day}/{part}", api.Solve)
+	apiMux.HandleFunc("POST /solvers/{day}/steps", api.Steps)
+	apiMux.HandleFunc("POST /solvers/{day}/validate", api.Validate)
+	apiMux.HandleFunc("POST /solvers/{day}/{part}/crosscheck", api.Crosscheck)
+	apiMux.HandleFunc("POST /detect", api.Detect)
+	apiMux.HandleFunc("GET /metrics", api.Metrics)
 
 	// public api
 	apiUnsecuredMux.HandleFunc("GET /info", api.Info)
@@ -0,0 +1,84 @@
+// Package provides metrics of the application
+// Metrics are kept in expvar types but not published by expvar, they are
+// served by the authenticated API without the command line and memory
+// statistics expvar publishes
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+
+	"advent2024/pkg/solver"
+)
+
+// Recovered panics of the solvers by day
+var SolverPanics = new(expvar.Map).Init()
+
+// Counters of the solver supervisor, see solver.SupervisorStats
+var Supervisor = expvar.Func(func() any {
+	return solver.Supervisor()
+})
+
+// Counts panic of the solver, callback of solver.OnPanic
+func CountPanic(p *solver.PanicError) {
+	SolverPanics.Add(p.Day, 1)
+}
+
+// Usage of successful solves by day and part, keyed e.g. d6/2
+// CPU time and allocated bytes measured for the whole process are
+// approximations including concurrent solves, kept apart from usage
+// reported by the solver or its child process
+var (
+	Solves                    = new(expvar.Map).Init()
+	SolveWallTime             = new(expvar.Map).Init()
+	SolveCPUTime              = new(expvar.Map).Init()
+	SolveAllocated            = new(expvar.Map).Init()
+	SolveProcessWideCPUTime   = new(expvar.Map).Init()
+	SolveProcessWideAllocated = new(expvar.Map).Init()
+)
+
+// Metrics by the name they are served with
+var vars = map[string]expvar.Var{
+	"solver_panics":                      SolverPanics,
+	"solver_supervisor":                  Supervisor,
+	"solves":                             Solves,
+	"solve_wall_ms":                      SolveWallTime,
+	"solve_cpu_ms":                       SolveCPUTime,
+	"solve_allocated_bytes":              SolveAllocated,
+	"solve_process_wide_cpu_ms":          SolveProcessWideCPUTime,
+	"solve_process_wide_allocated_bytes": SolveProcessWideAllocated,
+}
+
+// Returns current values of all metrics by name
+func Snapshot() map[string]json.RawMessage {
+	result := make(map[string]json.RawMessage, len(vars))
+
+	for name, v := range vars {
+		result[name] = json.RawMessage(v.String())
+	}
+
+	return result
+}
+
+// Adds usage of the successful solve of the day
+// Variants are counted apart from the default implementation, e.g. d8/2:parallel
+func RecordSolve(day string, r solver.Result) {
+	key := fmt.Sprintf("%s/%d", day, r.Part)
+
+	if r.Variant != "" {
+		key += ":" + r.Variant
+	}
+
+	Solves.Add(key, 1)
+	SolveWallTime.AddFloat(key, float64(r.SolveTime.Microseconds())/1000)
+
+	cpu, allocated := SolveCPUTime, SolveAllocated
+
+	if r.ProcessWide {
+		cpu, allocated = SolveProcessWideCPUTime, SolveProcessWideAllocated
+	}
+
+	cpu.AddFloat(key, float64(r.CPUTime.Microseconds())/1000)
+	allocated.Add(key, int64(r.Allocated))
+}
@@ -50,6 +50,11 @@ func (lrw *loggingResponseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Returns the wrapped writer, allows flushing by http.ResponseController
+func (lrw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return lrw.ResponseWriter
+}
+
 // Creates new longer based on the configuration
 // TODO: add more options
 func NewLogger(c *config.Config) *log.Logger {
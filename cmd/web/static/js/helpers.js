@@ -71,7 +71,7 @@ function createTable(elId, data, headerFunc, rowFunc) {
 }
 
 function solverListingHeaderFunc(headerRow, data) {
-  const headers = ["Name", "Parts 1", "Parts 2"];
+  const headers = ["Name", "Title", "Parts"];
 
   for (const header of headers) {
       const cell = document.createElement("th")
@@ -85,19 +85,96 @@ function solverListingRowFunc(row, data) {
   dayCell.textContent = data.name;
   dayCell.classList.add("row-key");
 
-  const part1Cell = row.insertCell();
-  const part1Link = document.createElement("a");
-  part1Link.href = "#";
-  part1Link.textContent = "Part 1";
-  part1Link.dataset.day = data.name;
-  part1Link.dataset.part = "1";
-  part1Cell.appendChild(part1Link);
-
-  const part2Cell = row.insertCell();
-  const part2Link = document.createElement("a");
-  part2Link.href = "#";
-  part2Link.textContent = "Part 2";
-  part2Link.dataset.day = data.name;
-  part2Link.dataset.part = "2";
-  part2Cell.appendChild(part2Link);
-}
\ No newline at end of file
+  const titleCell = row.insertCell();
+  titleCell.textContent = data.title;
+  if (data.description) {
+    titleCell.title = data.description;
+  }
+
+  const partsCell = row.insertCell();
+  for (const part of data.parts || []) {
+    const partLink = document.createElement("a");
+    partLink.href = "#";
+    partLink.textContent = part.label || `Part ${part.part}`;
+    partLink.dataset.day = data.name;
+    partLink.dataset.part = String(part.part);
+
+    if (partsCell.childNodes.length > 0) {
+      partsCell.appendChild(document.createElement("br"));
+    }
+    partsCell.appendChild(partLink);
+  }
+}
+
+// renders solve response with its value, times and statistics
+function formatSolveResult(response) {
+  if (response && response.errormessage !== undefined) {
+    return formatError(response);
+  }
+
+  if (!response || response.output === undefined) {
+    return JSON.stringify(response, null, 2);
+  }
+
+  const lines = [];
+
+  lines.push(response.part ? `Part ${response.part}: ${response.output}` : response.output);
+
+  if (response.variant) {
+    lines.push(`Variant: ${response.variant}`);
+  }
+
+  if (response.valueType) {
+    lines.push(`Value type: ${response.valueType}`);
+  }
+
+  if (response.parseTimeMs !== undefined) {
+    lines.push(`Parse time: ${response.parseTimeMs} ms`);
+  }
+
+  if (response.solveTimeMs !== undefined) {
+    lines.push(`Solve time: ${response.solveTimeMs} ms`);
+  }
+
+  const usage = response.processWideUsage ? " (process-wide approximation)" : "";
+
+  if (response.cpuTimeMs !== undefined) {
+    lines.push(`CPU time: ${response.cpuTimeMs} ms${usage}`);
+  }
+
+  if (response.allocatedBytes !== undefined) {
+    lines.push(`Allocated: ${response.allocatedBytes} B${usage}`);
+  }
+
+  if (response.stats) {
+    for (const key of Object.keys(response.stats).sort()) {
+      lines.push(`${key}: ${response.stats[key]}`);
+    }
+  }
+
+  return lines.join("\n");
+}
+
+// renders API error, one line per invalid input position
+function formatError(response) {
+  const lines = [`Error ${response.errorcode}: ${response.errormessage}`];
+
+  for (const d of response.details || []) {
+    let where = "Input";
+    if (d.line > 0) {
+      where = d.column > 0 ? `Line ${d.line}, column ${d.column}` : `Line ${d.line}`;
+    }
+
+    let line = `${where}: ${d.message}`;
+    if (d.found) {
+      line += ` "${d.found}"`;
+    }
+    if (d.expected) {
+      line += ` (expected ${d.expected})`;
+    }
+
+    lines.push(line);
+  }
+
+  return lines.join("\n");
+}
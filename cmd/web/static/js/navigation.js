@@ -467,6 +467,27 @@ document.getElementById("submitBtn").addEventListener("click", async () => {
   }
 });
 
+// detect button
+// selects the best candidate day, keeps the selected part
+document.getElementById("detectBtn").addEventListener("click", async () => {
+  const detectedEl = document.getElementById("detected");
+  try {
+    const base64 = await readInput();
+    const response = await sendToApi("POST", "/api/detect", { input: base64 });
+    const best = response.candidates && response.candidates[0];
+
+    if (!best) {
+      detectedEl.textContent = response.errormessage || "No day matches the input";
+      return;
+    }
+
+    detectedEl.textContent = `Detected ${best.day} ${best.title} (score ${best.score})`;
+    UIHandler.transition('SELECT', {day: best.day, part: sessionStorage.getItem("part") || "1"});
+  } catch(error) {
+    detectedEl.textContent = error.message;
+  }
+});
+
 // file input change
 // modifies selected file display
 document.getElementById("fileInput").addEventListener("change", e => {
@@ -494,36 +515,49 @@ document.getElementById("inputClear").addEventListener("click", () => {
  * @returns 
  */
 async function handleSubmitClick(endpointTemplate) {
-  const input = document.getElementById('fileInput');
-  const file = input.files[0];
-
-  const textInput = document.getElementById('textInput');
-  const text = textInput.value;
   let apiEndpoint
   try {
       apiEndpoint = fillTemplateFromSession(endpointTemplate)
   } catch (error) {
     throw Error("local error: " + error);
   }
-  
+
+  const base64 = await readInput();
+
+  // send request to API and display return value
+  try {
+    const response = await sendToApi("POST", apiEndpoint, { input: base64 })
+    return formatSolveResult(response)
+  } catch(error) {
+    throw Error("backend error: " + error.message);
+  }
+}
+
+/**
+ * Reads the selected file or the text area
+ *
+ * @returns {Promise<string>} base64 encoded input
+ * @throws {Error} If nothing is selected or the file can't be read
+ */
+async function readInput() {
+  const input = document.getElementById('fileInput');
+  const file = input.files[0];
+
+  const textInput = document.getElementById('textInput');
+  const text = textInput.value;
+
   // check if something was filled
   // display error if not
   if (!file && text.trim() === "" ) {
     throw Error("local error: Please select a file or input text first");
   }
-  
-  // base64 encode the input, send request to API and display return value
+
   try {
-    let base64;
     if (file) {
-      base64 = await toBase64(file);
-    } else {
-      base64 = btoa(unescape(encodeURIComponent(text)));
+      return await toBase64(file);
     }
-    
-    const response = await sendToApi("POST", apiEndpoint, { input: base64 })
-    return JSON.stringify(response, null, 2)
+    return btoa(unescape(encodeURIComponent(text)));
   } catch(error) {
-    throw Error("backend error: " + error.message);
+    throw Error("local error: " + error.message);
   }
 }
@@ -0,0 +1,792 @@
+// Tests for solver API
+package tests
+
+import (
+	"advent2024/pkg/solver"
+	"advent2024/web/api"
+	"advent2024/web/config"
+	"advent2024/web/metrics"
+	"advent2024/web/middleware"
+	"advent2024/web/weberrors"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	_ "advent2024/pkg/d1"
+	_ "advent2024/pkg/d10"
+	_ "advent2024/pkg/d11"
+	_ "advent2024/pkg/d2"
+	_ "advent2024/pkg/d3"
+	_ "advent2024/pkg/d4"
+	_ "advent2024/pkg/d5"
+	_ "advent2024/pkg/d6"
+	_ "advent2024/pkg/d7"
+	_ "advent2024/pkg/d8"
+	_ "advent2024/pkg/d9"
+)
+
+var inputD6 = `....#.....
+.........#
+..........
+..#.......
+.......#..
+..........
+.#..^.....
+........#.
+#.........
+......#...`
+
+var inputD8 = `............
+........0...
+.....0......
+.......0....
+....0.......
+......A.....
+............
+............
+........A...
+.........A..
+............
+............`
+
+func TestSteps(t *testing.T) {
+	// create config
+	cfg := config.NewConfig()
+
+	// setup the router
+	mux := http.NewServeMux()
+	mux.Handle("POST /solvers/{day}/steps",
+		middleware.Chain(
+			http.HandlerFunc(api.Steps),
+			middleware.WithConfig(&cfg)))
+
+	input := base64.StdEncoding.EncodeToString([]byte(inputD6))
+
+	cases := []struct {
+		name      string
+		day       string
+		body      string
+		want      int
+		wantSteps int
+		wantDone  bool
+	}{
+		{"first steps", "d6", fmt.Sprintf(`{"input": "%s", "start": 0, "count": 5}`, input), http.StatusOK, 5, false},
+		{"skipped steps", "d6", fmt.Sprintf(`{"input": "%s", "start": 40, "count": 5}`, input), http.StatusOK, 5, false},
+		{"last steps", "d6", fmt.Sprintf(`{"input": "%s", "start": 50, "count": 100}`, input), http.StatusOK, 6, true},
+		{"zero count", "d6", fmt.Sprintf(`{"input": "%s", "start": 0, "count": 0}`, input), http.StatusBadRequest, 0, false},
+		{"invalid input", "d6", `{"input": "bm9ndWFyZAo=", "start": 0, "count": 1}`, http.StatusBadRequest, 0, false},
+		{"not stepper", "d1", fmt.Sprintf(`{"input": "%s", "start": 0, "count": 1}`, input), http.StatusNotFound, 0, false},
+		{"unknown day", "d99", fmt.Sprintf(`{"input": "%s", "start": 0, "count": 1}`, input), http.StatusNotFound, 0, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/solvers/"+c.day+"/steps", strings.NewReader(c.body))
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			if w.Code != c.want {
+				t.Fatalf("got %d, want %d", w.Code, c.want)
+			}
+
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var result api.StepResult
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+
+			if len(result.Steps) != c.wantSteps || result.Done != c.wantDone {
+				t.Errorf("got %d steps done %t, want %d steps done %t", len(result.Steps), result.Done, c.wantSteps, c.wantDone)
+			}
+		})
+	}
+}
+
+func TestSolverListing(t *testing.T) {
+	req := httptest.NewRequest("GET", "/solvers", nil)
+	w := httptest.NewRecorder()
+
+	api.SolverListing(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var items []solver.RegistryItemPublic
+	if err := json.Unmarshal(w.Body.Bytes(), &items); err != nil {
+		t.Fatalf("unable to unmarshal response: %v", err)
+	}
+
+	for _, item := range items {
+		if item.Name != "d6" {
+			continue
+		}
+
+		if item.Title != "Guard Gallivant" || len(item.Parts) != 2 || item.Parts[0].ExampleAnswer != "41" {
+			t.Errorf("got %+v, want metadata of d6", item)
+		}
+		return
+	}
+
+	t.Errorf("d6 not listed")
+}
+
+func TestSolveUnknownPart(t *testing.T) {
+	// create config
+	cfg := config.NewConfig()
+
+	// setup the router
+	mux := http.NewServeMux()
+	mux.Handle("POST /solvers/{day}/{part}",
+		middleware.Chain(
+			http.HandlerFunc(api.Solve),
+			middleware.WithConfig(&cfg)))
+
+	input := base64.StdEncoding.EncodeToString([]byte(inputD6))
+
+	cases := []struct {
+		name string
+		part string
+		want int
+	}{
+		{"known part", "1", http.StatusOK},
+		{"unknown part", "3", http.StatusNotFound},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"input": "%s"}`, input)
+			req := httptest.NewRequest("POST", "/solvers/d6/"+c.part, strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			if w.Code != c.want {
+				t.Errorf("got %d, want %d", w.Code, c.want)
+			}
+		})
+	}
+}
+
+func TestSolveInvalidInput(t *testing.T) {
+	// create config
+	cfg := config.NewConfig()
+
+	// setup the router
+	mux := http.NewServeMux()
+	mux.Handle("POST /solvers/{day}/{part}",
+		middleware.Chain(
+			http.HandlerFunc(api.Solve),
+			middleware.WithConfig(&cfg)))
+
+	cases := []struct {
+		name  string
+		day   string
+		input string
+		want  []weberrors.ErrorDetail
+	}{
+		{"unknown character", "d6", "....\n..x.\n", []weberrors.ErrorDetail{{Line: 2, Column: 3, Expected: "one of .#^>v<", Found: "x", Message: "unknown character"}}},
+		{"not a number", "d1", "3 4\n4 y3\n", []weberrors.ErrorDetail{{Line: 2, Column: 3, Expected: "number", Found: "y3", Message: "not a number"}}},
+		{"empty input", "d1", "\n", []weberrors.ErrorDetail{{Expected: "non-empty input", Message: "empty input"}}},
+		{"negative stone", "d11", "125 -17\n", []weberrors.ErrorDetail{{Line: 1, Column: 5, Expected: "non-negative number", Found: "-17", Message: "negative stone"}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"input": "%s"}`, base64.StdEncoding.EncodeToString([]byte(c.input)))
+			req := httptest.NewRequest("POST", "/solvers/"+c.day+"/1", strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("got %d, want %d", w.Code, http.StatusBadRequest)
+			}
+
+			var result weberrors.AoCError
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+
+			if !reflect.DeepEqual(result.Details, c.want) {
+				t.Errorf("got %+v, want %+v", result.Details, c.want)
+			}
+		})
+	}
+}
+
+func TestErrorDetails(t *testing.T) {
+	err := fmt.Errorf("d42 unable to read input: %w", solver.ErrInvalidInput)
+
+	want := []weberrors.ErrorDetail{{Message: "d42 unable to read input: invalid input"}}
+
+	if got := weberrors.Details(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if got := weberrors.Details(solver.ErrTimeout); got != nil {
+		t.Errorf("got %+v, want no details", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	// create config
+	cfg := config.NewConfig()
+
+	// setup the router
+	mux := http.NewServeMux()
+	mux.Handle("POST /solvers/{day}/validate",
+		middleware.Chain(
+			http.HandlerFunc(api.Validate),
+			middleware.WithConfig(&cfg)))
+
+	cases := []struct {
+		name      string
+		day       string
+		input     string
+		want      int
+		wantValid bool
+		wantFacts map[string]int64
+		wantLine  int
+	}{
+		{"valid grid", "d6", inputD6, http.StatusOK, true, map[string]int64{"width": 10, "height": 10}, 0},
+		{"valid without facts", "d1", "3 4\n4 3\n", http.StatusOK, true, nil, 0},
+		{"invalid input", "d1", "3 4\n4 y3\n", http.StatusOK, false, nil, 2},
+		{"unknown day", "d99", "3 4\n", http.StatusNotFound, false, nil, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"input": "%s"}`, base64.StdEncoding.EncodeToString([]byte(c.input)))
+			req := httptest.NewRequest("POST", "/solvers/"+c.day+"/validate", strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			if w.Code != c.want {
+				t.Fatalf("got %d, want %d", w.Code, c.want)
+			}
+
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var result api.ValidateResult
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+
+			if result.Valid != c.wantValid || !reflect.DeepEqual(result.Facts, c.wantFacts) {
+				t.Errorf("got valid %t facts %v, want %t %v", result.Valid, result.Facts, c.wantValid, c.wantFacts)
+			}
+
+			if c.wantLine > 0 && (len(result.Details) != 1 || result.Details[0].Line != c.wantLine) {
+				t.Errorf("got details %+v, want line %d", result.Details, c.wantLine)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	// create config
+	cfg := config.NewConfig()
+
+	// setup the router
+	mux := http.NewServeMux()
+	mux.Handle("POST /detect",
+		middleware.Chain(
+			http.HandlerFunc(api.Detect),
+			middleware.WithConfig(&cfg)))
+
+	// example of every day is detected as the day
+	for _, item := range solver.ListRegistryItems() {
+		if item.Example == "" {
+			continue
+		}
+
+		t.Run(item.Name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"input": "%s"}`, base64.StdEncoding.EncodeToString([]byte(item.Example)))
+			req := httptest.NewRequest("POST", "/detect", strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("got %d, want %d", w.Code, http.StatusOK)
+			}
+
+			var result api.DetectResult
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+
+			if len(result.Candidates) == 0 || result.Candidates[0].Day != item.Name {
+				t.Errorf("got %+v, want %s first", result.Candidates, item.Name)
+			}
+
+			// d8 parses every grid, other grids have no antenna pairs
+			for _, c := range result.Candidates {
+				if c.Day == "d8" && item.Name != "d8" {
+					t.Errorf("got d8 candidate with score %v, want it skipped", c.Score)
+				}
+			}
+		})
+	}
+}
+
+// Solver panicking while solving
+type panickingSolver struct{}
+
+func (p *panickingSolver) Init(reader io.Reader) error {
+	return nil
+}
+
+func (p *panickingSolver) Solve(part int) (string, error) {
+	var field [][]byte
+	return string(field[part][0]), nil
+}
+
+func TestSolvePanic(t *testing.T) {
+	solver.Register("test-panic", func() solver.PuzzleSolver { return &panickingSolver{} })
+	solver.OnPanic(metrics.CountPanic)
+	defer solver.OnPanic(nil)
+
+	// create config
+	cfg := config.NewConfig()
+
+	// setup the router
+	mux := http.NewServeMux()
+	mux.Handle("POST /solvers/{day}/{part}",
+		middleware.Chain(
+			http.HandlerFunc(api.Solve),
+			middleware.WithConfig(&cfg)))
+
+	body := fmt.Sprintf(`{"input": "%s"}`, base64.StdEncoding.EncodeToString([]byte("input")))
+	req := httptest.NewRequest("POST", "/solvers/test-panic/1", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("got %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+
+	var result weberrors.AoCError
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("unable to unmarshal response: %v", err)
+	}
+
+	if result.Kind != "solver_panic" {
+		t.Errorf("got kind %q, want solver_panic", result.Kind)
+	}
+
+	if got := metrics.SolverPanics.Get("test-panic"); got == nil || got.String() != "1" {
+		t.Errorf("got %v panics counted, want 1", got)
+	}
+}
+
+func TestSolveAll(t *testing.T) {
+	// create config
+	cfg := config.NewConfig()
+
+	// setup the router
+	mux := http.NewServeMux()
+	mux.Handle("POST /solvers/{day}",
+		middleware.Chain(
+			http.HandlerFunc(api.SolveAll),
+			middleware.WithConfig(&cfg)))
+
+	input := base64.StdEncoding.EncodeToString([]byte(inputD6))
+
+	cases := []struct {
+		name        string
+		day         string
+		body        string
+		want        int
+		wantOutputs []string
+	}{
+		{"all parts", "d6", fmt.Sprintf(`{"input": "%s"}`, input), http.StatusOK, []string{"41", "6"}},
+		{"invalid input", "d6", `{"input": "bm9ndWFyZAo="}`, http.StatusBadRequest, nil},
+		{"invalid base64", "d6", `{"input": "%%%"}`, http.StatusBadRequest, nil},
+		{"unknown day", "d99", fmt.Sprintf(`{"input": "%s"}`, input), http.StatusNotFound, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/solvers/"+c.day, strings.NewReader(c.body))
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			if w.Code != c.want {
+				t.Fatalf("got %d, want %d", w.Code, c.want)
+			}
+
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var result api.SolveAllResult
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+
+			if len(result.Results) != len(c.wantOutputs) {
+				t.Fatalf("got %d results, want %d", len(result.Results), len(c.wantOutputs))
+			}
+
+			for i, r := range result.Results {
+				if r.Part != i+1 || r.Output != c.wantOutputs[i] || r.Error != "" {
+					t.Errorf("got %+v, want part %d output %s", r, i+1, c.wantOutputs[i])
+				}
+
+				if r.ValueType != "int" || r.Stats["visited"] != 41 {
+					t.Errorf("got %+v, want int value with 41 visited", r)
+				}
+
+				if got := metrics.Solves.Get(fmt.Sprintf("d6/%d", i+1)); got == nil {
+					t.Errorf("solve of part %d not recorded", i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestSolveVariant(t *testing.T) {
+	// create config
+	cfg := config.NewConfig()
+
+	// setup the router
+	mux := http.NewServeMux()
+	mux.Handle("POST /solvers/{day}/{part}",
+		middleware.Chain(
+			http.HandlerFunc(api.Solve),
+			middleware.WithConfig(&cfg)))
+
+	body := fmt.Sprintf(`{"input": "%s"}`, base64.StdEncoding.EncodeToString([]byte(inputD8)))
+
+	cases := []struct {
+		name    string
+		url     string
+		want    int
+		variant string
+	}{
+		{"default", "/solvers/d8/2", http.StatusOK, ""},
+		{"sequential", "/solvers/d8/2?variant=sequential", http.StatusOK, "sequential"},
+		{"parallel", "/solvers/d8/2?variant=parallel", http.StatusOK, "parallel"},
+		{"unknown variant", "/solvers/d8/2?variant=quantum", http.StatusNotFound, ""},
+		{"part without variants", "/solvers/d8/1?variant=parallel", http.StatusNotFound, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", c.url, strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			if w.Code != c.want {
+				t.Fatalf("got %d, want %d", w.Code, c.want)
+			}
+
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var result api.SolveResult
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+
+			if result.Output != "34" || result.Variant != c.variant {
+				t.Errorf("got %+v, want output 34 of variant %q", result, c.variant)
+			}
+		})
+	}
+}
+
+func TestCrosscheck(t *testing.T) {
+	// create config
+	cfg := config.NewConfig()
+
+	// setup the router
+	mux := http.NewServeMux()
+	mux.Handle("POST /solvers/{day}/{part}/crosscheck",
+		middleware.Chain(
+			http.HandlerFunc(api.Crosscheck),
+			middleware.WithConfig(&cfg)))
+
+	input := base64.StdEncoding.EncodeToString([]byte(inputD8))
+
+	cases := []struct {
+		name         string
+		url          string
+		body         string
+		want         int
+		wantVariants []string
+	}{
+		{"variants", "/solvers/d8/2/crosscheck", fmt.Sprintf(`{"input": "%s"}`, input), http.StatusOK, []string{"sequential", "parallel"}},
+		{"part without variants", "/solvers/d8/1/crosscheck", fmt.Sprintf(`{"input": "%s"}`, input), http.StatusNotFound, nil},
+		{"unknown day", "/solvers/d99/2/crosscheck", fmt.Sprintf(`{"input": "%s"}`, input), http.StatusNotFound, nil},
+		{"invalid input", "/solvers/d8/2/crosscheck", `{"input": "bm9ncmlkCng="}`, http.StatusBadRequest, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", c.url, strings.NewReader(c.body))
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			if w.Code != c.want {
+				t.Fatalf("got %d, want %d", w.Code, c.want)
+			}
+
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var result api.CrosscheckResult
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+
+			if !result.Agree || len(result.Disagree) != 0 || len(result.Results) != len(c.wantVariants) {
+				t.Fatalf("got %+v, want agreeing %v", result, c.wantVariants)
+			}
+
+			for i, r := range result.Results {
+				if r.Variant != c.wantVariants[i] || r.Output != "34" || r.Error != "" {
+					t.Errorf("got %+v, want output 34 of variant %s", r, c.wantVariants[i])
+				}
+			}
+
+			if got := metrics.Solves.Get("d8/2:parallel"); got == nil {
+				t.Errorf("solve of variant parallel not recorded")
+			}
+		})
+	}
+}
+
+func TestSolveBigInt(t *testing.T) {
+	// create config
+	cfg := config.NewConfig()
+
+	// setup the router
+	mux := http.NewServeMux()
+	mux.Handle("POST /solvers/{day}/{part}",
+		middleware.Chain(
+			http.HandlerFunc(api.Solve),
+			middleware.WithConfig(&cfg)))
+
+	input := "6000000000000000000: 6000000000000000000 1\n6000000000000000000: 6000000000000000000 1"
+	body := fmt.Sprintf(`{"input": "%s"}`, base64.StdEncoding.EncodeToString([]byte(input)))
+
+	req := httptest.NewRequest("POST", "/solvers/d7/1", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var result struct {
+		Output    string          `json:"output"`
+		Value     json.RawMessage `json:"value"`
+		ValueType string          `json:"valueType"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("unable to unmarshal response: %v", err)
+	}
+
+	want := "12000000000000000000"
+
+	if result.Output != want || string(result.Value) != want || result.ValueType != "bigint" {
+		t.Errorf("got %+v, want bigint %s", result, want)
+	}
+}
+
+func TestSolveStream(t *testing.T) {
+	// create config
+	cfg := config.NewConfig()
+
+	// setup the router
+	mux := http.NewServeMux()
+	mux.Handle("POST /solvers/{day}/{part}",
+		middleware.Chain(
+			http.HandlerFunc(api.Solve),
+			middleware.WithConfig(&cfg)))
+
+	input := base64.StdEncoding.EncodeToString([]byte(inputD6))
+
+	cases := []struct {
+		name        string
+		part        string
+		wantLines   bool
+		wantOutput  string
+		contentType string
+	}{
+		{"progress streamed", "2", true, "6", "application/x-ndjson"},
+		{"no progress reported", "1", false, "41", "application/json"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"input": "%s"}`, input)
+			req := httptest.NewRequest("POST", "/solvers/d6/"+c.part, strings.NewReader(body))
+			req.Header.Set("Accept", "application/x-ndjson")
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("got %d, want %d", w.Code, http.StatusOK)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != c.contentType {
+				t.Errorf("got content type %s, want %s", got, c.contentType)
+			}
+
+			if !c.wantLines {
+				var result api.SolveResult
+				if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil || result.Output != c.wantOutput {
+					t.Errorf("got %s, want output %s", w.Body.String(), c.wantOutput)
+				}
+				return
+			}
+
+			lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+
+			for _, line := range lines[:len(lines)-1] {
+				var l api.StreamLine
+				if err := json.Unmarshal([]byte(line), &l); err != nil || l.Progress == nil {
+					t.Fatalf("got line %s, want progress", line)
+				}
+			}
+
+			var last struct {
+				Result api.SolveResult `json:"result"`
+			}
+			if err := json.Unmarshal([]byte(lines[len(lines)-1]), &last); err != nil || last.Result.Output != c.wantOutput {
+				t.Errorf("got last line %s, want output %s", lines[len(lines)-1], c.wantOutput)
+			}
+		})
+	}
+}
+
+func TestSolveParams(t *testing.T) {
+	// create config
+	cfg := config.NewConfig()
+
+	// setup the router
+	mux := http.NewServeMux()
+	mux.Handle("POST /solvers/{day}/{part}",
+		middleware.Chain(
+			http.HandlerFunc(api.Solve),
+			middleware.WithConfig(&cfg)))
+
+	input := base64.StdEncoding.EncodeToString([]byte("125 17\n"))
+
+	cases := []struct {
+		name   string
+		url    string
+		params string
+		want   int
+		output string
+	}{
+		{"defaults", "/solvers/d11/1", "", http.StatusOK, "55312"},
+		{"query", "/solvers/d11/1?blinks=6", "", http.StatusOK, "22"},
+		{"body number", "/solvers/d11/1", `"params": {"blinks": 6},`, http.StatusOK, "22"},
+		{"body string", "/solvers/d11/1", `"params": {"blinks": "6"},`, http.StatusOK, "22"},
+		{"out of bounds", "/solvers/d11/1?blinks=50000", "", http.StatusBadRequest, ""},
+		{"not a number", "/solvers/d11/1?blinks=many", "", http.StatusBadRequest, ""},
+		{"unknown parameter", "/solvers/d11/1", `"params": {"stones": 6},`, http.StatusBadRequest, ""},
+		{"solver without parameters", "/solvers/d1/1", `"params": {"blinks": 6},`, http.StatusBadRequest, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			body := fmt.Sprintf(`{%s "input": "%s"}`, c.params, input)
+			req := httptest.NewRequest("POST", c.url, strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			if w.Code != c.want {
+				t.Fatalf("got %d, want %d: %s", w.Code, c.want, w.Body.String())
+			}
+
+			if w.Code != http.StatusOK {
+				var e weberrors.AoCError
+				if err := json.Unmarshal(w.Body.Bytes(), &e); err != nil {
+					t.Fatalf("unable to unmarshal response: %v", err)
+				}
+
+				if e.Kind != "invalid_param" {
+					t.Errorf("got kind %q, want invalid_param", e.Kind)
+				}
+
+				return
+			}
+
+			var result api.SolveResult
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatalf("unable to unmarshal response: %v", err)
+			}
+
+			if result.Output != c.output {
+				t.Errorf("got %s, want %s", result.Output, c.output)
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	// create config
+	cfg := config.NewConfig()
+
+	// setup the router
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics",
+		middleware.Chain(
+			http.HandlerFunc(api.Metrics),
+			middleware.WithConfig(&cfg)))
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("unable to unmarshal response: %v", err)
+	}
+
+	for _, name := range []string{"solves", "solver_panics", "solver_supervisor", "solve_cpu_ms", "solve_process_wide_cpu_ms"} {
+		if _, ok := result[name]; !ok {
+			t.Errorf("metric %s missing in %s", name, w.Body.String())
+		}
+	}
+
+	// process arguments carry secrets
+	for _, name := range []string{"cmdline", "memstats"} {
+		if _, ok := result[name]; ok {
+			t.Errorf("got %s, want only solver metrics", name)
+		}
+	}
+}
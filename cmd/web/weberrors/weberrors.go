@@ -6,27 +6,101 @@ import (
 	"errors"
 	"log"
 	"net/http"
+
+	"advent2024/pkg/parse"
+	"advent2024/pkg/solver"
 )
 
 // Error sent by application for web responses, API responses
+// Kind classifies errors of the solvers, Details lists the positioned
+// errors of the puzzle input, invalid input without them has the error
+// text as the only detail
 type AoCError struct {
-	ErrorCode    int    `json:"errorcode"`
-	ErrorMessage string `json:"errormessage"`
+	ErrorCode    int           `json:"errorcode"`
+	ErrorMessage string        `json:"errormessage"`
+	Kind         string        `json:"kind,omitempty" enums:"invalid_input,timeout,overloaded,unknown_part,unknown_variant,invalid_param,solver_panic"`
+	Details      []ErrorDetail `json:"details,omitempty"`
 } //@name Error
 
+// Positioned error of the puzzle input
+// Line and Column are 1-based, 0 when the error is not bound to the line or column
+type ErrorDetail struct {
+	Line     int    `json:"line" example:"3"`
+	Column   int    `json:"column" example:"5"`
+	Expected string `json:"expected,omitempty" example:"number"`
+	Found    string `json:"found,omitempty" example:"x1"`
+	Message  string `json:"message" example:"not a number"`
+} //@name ErrorDetail
+
 // Constructor
 func NewError(status int, message string) AoCError {
 	return AoCError{ErrorCode: status, ErrorMessage: message}
 }
 
+// Constructor, kind and details are collected from err
+func NewErrorWithDetails(status int, message string, err error) AoCError {
+	return AoCError{ErrorCode: status, ErrorMessage: message, Kind: Kind(err), Details: Details(err)}
+}
+
+// Returns kind of the solver error, empty for other errors
+func Kind(err error) string {
+	switch {
+	case errors.Is(err, solver.ErrSolverPanic):
+		return "solver_panic"
+	case errors.Is(err, solver.ErrInvalidInput):
+		return "invalid_input"
+	case errors.Is(err, solver.ErrTimeout):
+		return "timeout"
+	case errors.Is(err, solver.ErrOverloaded):
+		return "overloaded"
+	case errors.Is(err, solver.ErrUnknownPart):
+		return "unknown_part"
+	case errors.Is(err, solver.ErrUnknownVariant):
+		return "unknown_variant"
+	case errors.Is(err, solver.ErrInvalidParam):
+		return "invalid_param"
+	}
+
+	return ""
+}
+
+// Returns details of all parse.InputError in the err tree
+// Invalid input without them is detailed by the text of err
+func Details(err error) []ErrorDetail {
+	var result []ErrorDetail
+
+	var walk func(err error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case nil:
+		case *parse.InputError:
+			result = append(result, ErrorDetail{Line: e.Line, Column: e.Column, Expected: e.Expected, Found: e.Found, Message: e.Reason})
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		}
+	}
+
+	walk(err)
+
+	if result == nil && errors.Is(err, solver.ErrInvalidInput) {
+		result = append(result, ErrorDetail{Message: err.Error()})
+	}
+
+	return result
+}
+
 // Handles errors - write response code and encapsulates err msg into json
 func HandleError(w http.ResponseWriter, logger *log.Logger, err error, httpErrorCode int, errMsg string) error {
 	if err != nil {
 		rc := httpErrorCode
 
-		errJson, _ := json.Marshal(NewError(rc, errMsg))
+		errJson, _ := json.Marshal(NewErrorWithDetails(rc, errMsg, err))
 
-		logger.Println(errMsg)
+		logger.Printf("%s: %v", errMsg, err)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(rc)
 		w.Write(errJson)
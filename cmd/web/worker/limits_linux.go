@@ -0,0 +1,23 @@
+//go:build linux
+
+package worker
+
+import "syscall"
+
+// Limits address space in bytes and CPU time in seconds of the process,
+// 0 keeps the limit, exceeding CPU time kills the process
+func setLimits(memory, cpu uint64) error {
+	if memory > 0 {
+		if err := syscall.Setrlimit(syscall.RLIMIT_AS, &syscall.Rlimit{Cur: memory, Max: memory}); err != nil {
+			return err
+		}
+	}
+
+	if cpu > 0 {
+		if err := syscall.Setrlimit(syscall.RLIMIT_CPU, &syscall.Rlimit{Cur: cpu, Max: cpu}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
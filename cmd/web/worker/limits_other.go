@@ -0,0 +1,9 @@
+//go:build !linux
+
+package worker
+
+// Resource limits are not supported, the worker is still killed when
+// the deadline passes
+func setLimits(memory, cpu uint64) error {
+	return nil
+}
@@ -0,0 +1,80 @@
+// Package provides isolated solving in child processes
+// The web binary re-executes itself in worker mode, the worker serves
+// one solver over the external solver protocol under resource limits
+package worker
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"advent2024/pkg/solver"
+)
+
+// First argument switching the binary into worker mode
+const Command = "solve-worker"
+
+// Resource limits of the worker process
+type Limits struct {
+	// address space in MiB, 0 is unlimited
+	Memory int
+	// CPU time, 0 is unlimited
+	CPU time.Duration
+}
+
+// Returns if the binary was started in worker mode
+func IsWorker(args []string) bool {
+	return len(args) > 1 && args[1] == Command
+}
+
+// Returns runners of workers started from the executable with the limits
+// Workers are killed when the deadline of the solve passes
+func Runner(exe string, limits Limits) func(name string) solver.ExternalRunner {
+	// CPU time is limited in whole seconds, the deadline comes first
+	cpu := int(math.Ceil(limits.CPU.Seconds()))
+
+	if cpu > 0 {
+		cpu++
+	}
+
+	return func(name string) solver.ExternalRunner {
+		return solver.ExecRunner(exe, Command,
+			"-memory-limit", strconv.Itoa(limits.Memory),
+			"-cpu-limit", strconv.Itoa(cpu),
+			name)
+	}
+}
+
+// Serves the solver named in args over stdin and stdout under the limits
+// given in args, args follow the Command
+func Serve(args []string) error {
+	fs := flag.NewFlagSet(Command, flag.ContinueOnError)
+
+	memory := fs.Int("memory-limit", 0, "Address space limit in MiB, 0 is unlimited")
+	cpu := fs.Int("cpu-limit", 0, "CPU time limit in seconds, 0 is unlimited")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%s: expected solver name", Command)
+	}
+
+	name := fs.Arg(0)
+
+	item, ok := solver.Lookup(name)
+
+	if !ok {
+		return fmt.Errorf("%s: %w", name, solver.ErrUnknownSolver)
+	}
+
+	if err := setLimits(uint64(*memory)*1024*1024, uint64(*cpu)); err != nil {
+		return fmt.Errorf("%s: unable to set limits: %w", Command, err)
+	}
+
+	return solver.ServeExternal(os.Stdin, os.Stdout, name, item.Constructor)
+}
@@ -5,15 +5,27 @@ import (
 	"bufio"
 	"fmt"
 	"io"
-	"log"
 	"strconv"
 
+	"advent2024/pkg/parse"
 	"advent2024/pkg/solver"
 )
 
 // Solver name
 var day = "d0"
 
+// Description of the puzzle
+var metadata = solver.Metadata{
+	Title:       "Skeleton",
+	Description: "Template for new days, solves nothing.",
+	Parts: []solver.Part{
+		{Part: 1, Label: "Part 1", ExampleAnswer: "0"},
+		{Part: 2, Label: "Part 2", ExampleAnswer: "0"},
+	},
+	InputFormat: "Any text.",
+	Example:     ``,
+}
+
 // PuzzleStruct
 type PuzzleStruct struct {
 	input string
@@ -22,7 +34,7 @@ type PuzzleStruct struct {
 // Registers day wih the registry
 func init() {
 	solver.Register(day, func() solver.PuzzleSolver {
-		return NewSolver()
+		return NewSolverWithCtx()
 	})
 }
 
@@ -31,18 +43,21 @@ func NewSolver() *PuzzleStruct {
 	return &PuzzleStruct{}
 }
 
+// Returns description of the puzzle
+func (p *PuzzleStruct) Metadata() solver.Metadata {
+	return metadata
+}
+
 // Initializes the PuzzleStruct with input
 // Return nil on success
 func (p *PuzzleStruct) Init(reader io.Reader) error {
 	input, err := parseInput(bufio.NewScanner(reader))
 
 	if err != nil {
-		log.Print(err)
 		return err
 	}
 
 	if err := validateInput(input); err != nil {
-		log.Print(err)
 		return err
 	}
 
@@ -70,12 +85,15 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 // Parses provided input
 // Returns parsed string
 func parseInput(sc *bufio.Scanner) (string, error) {
+	line := 0
 
 	for sc.Scan() {
+		line++
 	}
 
+	// the line after the last scanned one can't be read
 	if sc.Err() != nil {
-		return "", fmt.Errorf("%s scan error %s: %w", day, sc.Err(), solver.ErrInvalidInput)
+		return "", fmt.Errorf("%s %w", day, parse.Errorf(line+1, 0, "", "", "%v", sc.Err()))
 	}
 
 	return "", nil
@@ -85,7 +103,7 @@ func parseInput(sc *bufio.Scanner) (string, error) {
 // Returns nil in case of successfull validation
 func validateInput(entry string) error {
 	if len(entry) == 0 {
-		return fmt.Errorf("%s empty records: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "records", "", "empty records"))
 	}
 
 	return nil
@@ -13,13 +13,6 @@ type PuzzleStructWithCtx struct {
 	PuzzleStruct
 }
 
-// Registers day wih the registry
-func init() {
-	solver.RegisterWithCtx(day, func() solver.PuzzleSolverWithCtx {
-		return NewSolverWithCtx()
-	})
-}
-
 // Constructor
 func NewSolverWithCtx() *PuzzleStructWithCtx {
 	return &PuzzleStructWithCtx{}
@@ -27,7 +20,7 @@ func NewSolverWithCtx() *PuzzleStructWithCtx {
 
 // Initializes the PuzzleStruct with input
 func (p *PuzzleStructWithCtx) InitCtx(ctx context.Context, reader io.Reader) error {
-	return p.PuzzleStruct.Init(reader)
+	return solver.InitWithCtx(ctx, reader, p.PuzzleStruct.Init)
 }
 
 // Solves the puzzle
@@ -1,26 +1,41 @@
 package d1
 
 import (
-	"bufio"
 	"fmt"
 	"io"
-	"log"
 	"slices"
 	"strconv"
-	"strings"
 
+	"advent2024/pkg/parse"
 	"advent2024/pkg/solver"
 )
 
 var day = "d1"
 
+// Description of the puzzle
+var metadata = solver.Metadata{
+	Title:       "Historian Hysteria",
+	Description: "Compares two lists of location IDs.",
+	Parts: []solver.Part{
+		{Part: 1, Label: "Total distance between the sorted lists", ExampleAnswer: "11"},
+		{Part: 2, Label: "Similarity score of the lists", ExampleAnswer: "31"},
+	},
+	InputFormat: "Lines with two whitespace separated integers, left and right list.",
+	Example: `3   4
+4   3
+2   5
+1   3
+3   9
+3   3`,
+}
+
 type PuzzleStruct struct {
 	input *[2][]int
 }
 
 func init() {
 	solver.Register(day, func() solver.PuzzleSolver {
-		return NewSolver()
+		return NewSolverWithCtx()
 	})
 }
 
@@ -28,16 +43,28 @@ func NewSolver() *PuzzleStruct {
 	return &PuzzleStruct{}
 }
 
+// Returns description of the puzzle
+func (p *PuzzleStruct) Metadata() solver.Metadata {
+	return metadata
+}
+
+// Returns how well the input fits, two lists have more than one line
+func (p *PuzzleStruct) Score() float64 {
+	if len(p.input[0]) < 2 {
+		return 0.3
+	}
+
+	return 0.9
+}
+
 func (p *PuzzleStruct) Init(reader io.Reader) error {
-	input, err := parseInput(bufio.NewScanner(reader))
+	input, err := parseInput(reader)
 
 	if err != nil {
-		log.Print(err)
 		return err
 	}
 
 	if err := validateInput(input); err != nil {
-		log.Print(err)
 		return err
 	}
 
@@ -77,33 +104,19 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
 }
 
-func parseInput(sc *bufio.Scanner) (*[2][]int, error) {
-
-	var left, right []int
+func parseInput(reader io.Reader) (*[2][]int, error) {
 
-	for sc.Scan() {
+	rows, err := parse.Ints(reader, 2)
 
-		if sc.Text() == "" {
-			continue
-		}
-
-		vs := strings.Fields(sc.Text())
-
-		if len(vs) != 2 {
-			maxOutput := min(len(sc.Text()), 80)
-			return nil, fmt.Errorf("%s unable to parse \"%v\": %w", day, sc.Text()[:maxOutput], solver.ErrInvalidInput)
-		}
-
-		l, lerr := strconv.Atoi(vs[0])
-		r, rerr := strconv.Atoi(vs[1])
+	if err != nil {
+		return nil, fmt.Errorf("%s %w", day, err)
+	}
 
-		if lerr != nil || rerr != nil {
-			maxOutput := min(len(sc.Text()), 80)
-			return nil, fmt.Errorf("%s unable to parse \"%v\": %w", day, sc.Text()[:maxOutput], solver.ErrInvalidInput)
-		}
+	var left, right []int
 
-		left = append(left, l)
-		right = append(right, r)
+	for _, row := range rows {
+		left = append(left, row[0])
+		right = append(right, row[1])
 	}
 
 	return &[2][]int{left, right}, nil
@@ -111,9 +124,9 @@ func parseInput(sc *bufio.Scanner) (*[2][]int, error) {
 
 func validateInput(entries *[2][]int) error {
 	if entries == nil {
-		return fmt.Errorf("%s empty records: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "records", "", "empty records"))
 	} else if len(entries[0]) == 0 || len(entries[1]) == 0 {
-		return fmt.Errorf("%s empty records: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "records", "", "empty records"))
 	}
 
 	return nil
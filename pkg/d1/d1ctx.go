@@ -14,18 +14,12 @@ type PuzzleStructWithCtx struct {
 	PuzzleStruct
 }
 
-func init() {
-	solver.RegisterWithCtx(day, func() solver.PuzzleSolverWithCtx {
-		return NewSolverWithCtx()
-	})
-}
-
 func NewSolverWithCtx() *PuzzleStructWithCtx {
 	return &PuzzleStructWithCtx{}
 }
 
 func (p *PuzzleStructWithCtx) InitCtx(ctx context.Context, reader io.Reader) error {
-	return p.PuzzleStruct.Init(reader)
+	return solver.InitWithCtx(ctx, reader, p.PuzzleStruct.Init)
 }
 
 func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, error) {
@@ -1,29 +1,42 @@
 package d10
 
 import (
-	"bufio"
 	"fmt"
 	"io"
-	"log"
 	"strconv"
-	"strings"
 
+	"advent2024/pkg/grid"
 	"advent2024/pkg/solver"
 )
 
 var day = "d10"
 
-type PuzzleStruct struct {
-	field *[][]int
+// Description of the puzzle
+var metadata = solver.Metadata{
+	Title:       "Hoof It",
+	Description: "Finds hiking trails on a topographic map.",
+	Parts: []solver.Part{
+		{Part: 1, Label: "Sum of trailhead scores", ExampleAnswer: "36"},
+		{Part: 2, Label: "Sum of trailhead ratings", ExampleAnswer: "81"},
+	},
+	InputFormat: "Rectangular grid of heights 0-9, . for impassable tiles.",
+	Example: `89010123
+78121874
+87430965
+96549874
+45678903
+32019012
+01329801
+10456732`,
 }
 
-type Coord struct {
-	x, y int
+type PuzzleStruct struct {
+	field *grid.Grid[int]
 }
 
 func init() {
 	solver.Register(day, func() solver.PuzzleSolver {
-		return NewSolver()
+		return NewSolverWithCtx()
 	})
 }
 
@@ -31,16 +44,30 @@ func NewSolver() *PuzzleStruct {
 	return &PuzzleStruct{}
 }
 
-func (p *PuzzleStruct) Init(reader io.Reader) error {
-	field, err := parseInput(bufio.NewScanner(reader))
+// Returns description of the puzzle
+func (p *PuzzleStruct) Metadata() solver.Metadata {
+	return metadata
+}
 
-	if err != nil {
-		log.Print(err)
-		return err
+// Returns summary of the parsed input
+func (p *PuzzleStruct) Describe() map[string]int64 {
+	return map[string]int64{"width": int64(p.field.Width()), "height": int64(p.field.Height())}
+}
+
+// Returns how well the input fits, single line is rather a disk map
+func (p *PuzzleStruct) Score() float64 {
+	if p.field.Height() < 2 {
+		return 0.2
 	}
 
-	if err := validateInput(field); err != nil {
-		log.Print(err)
+	return 0.9
+}
+
+func (p *PuzzleStruct) Init(reader io.Reader) error {
+	field, err := grid.Parse(reader, parseHeight)
+
+	if err != nil {
+		err = fmt.Errorf("%s %w", day, err)
 		return err
 	}
 
@@ -72,84 +99,27 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
 }
 
-func parseInput(sc *bufio.Scanner) (*[][]int, error) {
-
-	result := make([][]int, 0)
-
-	for sc.Scan() {
-		// for test return fed input
-		s := strings.TrimSpace(sc.Text())
-
-		if s == "" {
-			continue
-		}
-
-		var line = make([]int, 0, len(s))
-
-		for _, c := range s {
-			switch c {
-			case '.':
-				line = append(line, -1)
-			case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-				line = append(line, int(c-'0'))
-			default:
-				return nil, fmt.Errorf("%s invalid character %s in input: %w", day, string(c), solver.ErrInvalidInput)
-			}
-		}
-
-		result = append(result, line)
-	}
-
-	if sc.Err() != nil {
-		return nil, fmt.Errorf("%s scan error %s: %w", day, sc.Err(), solver.ErrInvalidInput)
+// Returns height of the tile, -1 for impassable tiles
+func parseHeight(b byte) (int, error) {
+	switch {
+	case b == '.':
+		return -1, nil
+	case b >= '0' && b <= '9':
+		return int(b - '0'), nil
 	}
 
-	return &result, nil
+	return 0, fmt.Errorf("not a height")
 }
 
-func validateInput(field *[][]int) error {
-	if field == nil {
-		return fmt.Errorf("%s empty records: %w", day, solver.ErrInvalidInput)
-	}
-
-	if len(*field) == 0 {
-		return fmt.Errorf("%s empty records: %w", day, solver.ErrInvalidInput)
-	}
-
-	rowLength := len((*field)[0])
-
-	for y := 0; y < len(*field); y++ {
-		if rowLength != len((*field)[y]) {
-			return fmt.Errorf("%s rows have unequal lengths: %w", day, solver.ErrInvalidInput)
-		}
-	}
-
-	return nil
+func (p *PuzzleStruct) FindZeroes() []grid.Point {
+	return p.field.Find(0)
 }
 
-func (p *PuzzleStruct) FindZeroes() []Coord {
-	var result []Coord
-
-	for y := 0; y < len(*p.field); y++ {
-		for x := 0; x < len((*p.field)[y]); x++ {
-
-			coord := Coord{x: x, y: y}
-			value, err := p.ValueAt(coord)
-
-			if err == nil && value == 0 {
-				result = append(result, coord)
-			}
-		}
-	}
-
-	return result
-}
-
-func (p *PuzzleStruct) ReachableSummits(from Coord) int {
+func (p *PuzzleStruct) ReachableSummits(from grid.Point) int {
 	return len(p.reachableSummits(from))
 }
 
-func (p *PuzzleStruct) PathsToSummits(from Coord) int {
+func (p *PuzzleStruct) PathsToSummits(from grid.Point) int {
 	var sum int
 
 	for _, v := range p.reachableSummits(from) {
@@ -159,8 +129,8 @@ func (p *PuzzleStruct) PathsToSummits(from Coord) int {
 	return sum
 }
 
-func (p *PuzzleStruct) reachableSummits(coord Coord) map[Coord]int {
-	var summits = make(map[Coord]int)
+func (p *PuzzleStruct) reachableSummits(coord grid.Point) map[grid.Point]int {
+	var summits = make(map[grid.Point]int)
 
 	value, err := p.ValueAt(coord)
 
@@ -182,7 +152,7 @@ func (p *PuzzleStruct) reachableSummits(coord Coord) map[Coord]int {
 	return summits
 }
 
-func (p *PuzzleStruct) ValueAt(coord Coord) (int, error) {
+func (p *PuzzleStruct) ValueAt(coord grid.Point) (int, error) {
 	if p == nil {
 		return -1, fmt.Errorf("puzzle struct is nil")
 	}
@@ -191,35 +161,23 @@ func (p *PuzzleStruct) ValueAt(coord Coord) (int, error) {
 		return -1, fmt.Errorf("field is nil")
 	}
 
-	if coord.y < 0 || coord.y >= len(*p.field) {
-		return -1, fmt.Errorf("coord %v outside of field", coord)
-	}
+	value, ok := p.field.Get(coord)
 
-	if coord.x < 0 || coord.x >= len((*p.field)[coord.y]) {
+	if !ok {
 		return -1, fmt.Errorf("coord %v outside of field", coord)
 	}
 
-	return (*p.field)[coord.y][coord.x], nil
+	return value, nil
 }
 
-func (p *PuzzleStruct) nextFrom(coord Coord) []Coord {
-
-	x, y := coord.x, coord.y
-	coordValue := (*p.field)[y][x]
+func (p *PuzzleStruct) nextFrom(coord grid.Point) []grid.Point {
 
-	var validNeighbors []Coord
-
-	neighbors := []Coord{
-		Coord{x: x, y: y - 1},
-		Coord{x: x + 1, y: y},
-		Coord{x: x, y: y + 1},
-		Coord{x: x - 1, y: y},
-	}
+	coordValue := p.field.At(coord)
 
-	for _, neighbor := range neighbors {
-		neighborValue, err := p.ValueAt(neighbor)
+	var validNeighbors []grid.Point
 
-		if err == nil && neighborValue-1 == coordValue {
+	for _, neighbor := range p.field.Neighbours4(coord) {
+		if p.field.At(neighbor)-1 == coordValue {
 			validNeighbors = append(validNeighbors, neighbor)
 		}
 	}
@@ -1,30 +1,73 @@
 package d11
 
 import (
-	"bufio"
+	"encoding/json"
 	"fmt"
 	"io"
-	"log"
-	"strconv"
-	"strings"
+	"math/big"
 
-	"container/list"
+	"context"
 
+	"advent2024/pkg/parse"
 	"advent2024/pkg/solver"
 )
 
 // Solver name
 var day = "d11"
 
+// Description of the puzzle
+var metadata = solver.Metadata{
+	Title:       "Plutonian Pebbles",
+	Description: "Counts stones which change or split on every blink.",
+	Parts: []solver.Part{
+		{Part: 1, Label: "Stones after 25 blinks", ExampleAnswer: "55312"},
+		{Part: 2, Label: "Stones after 75 blinks", ExampleAnswer: "65601038650482"},
+	},
+	Params: []solver.Param{
+		{Name: "blinks", Type: solver.ParamInt, Description: "Blinks of both parts, 0 keeps 25 blinks of part 1 and 75 of part 2", Default: "0", Min: 0, Max: 10000},
+	},
+	InputFormat: "Single line of whitespace separated integers.",
+	Example:     `125 17`,
+}
+
+// Number of blinks for each part
+const (
+	blinksPart1 = 25
+	blinksPart2 = 75
+)
+
+// Stones with less distinct values are listed in the Step
+const maxListedStones = 64
+
 // PuzzleStruct
+// Blinks override the blinks of both parts, 0 keeps them
 type PuzzleStruct struct {
-	l *list.List
+	stones   []int
+	blinks   int
+	blinking blinking
+}
+
+// Stones stored as value -> count after the round of blinking
+// Stones are promoted to large once their values or counts overflow int
+type blinking struct {
+	stones map[int]int
+	large  bigStones
+	round  int
+}
+
+// Snapshot of the stones after a blink
+type Step struct {
+	Round    int                 `json:"round"`
+	Stones   *big.Int            `json:"stones"`
+	Distinct int                 `json:"distinct"`
+	Counts   map[string]*big.Int `json:"counts,omitempty"`
+	Done     bool                `json:"done"`
 }
 
 // Registers day wih the registry
 func init() {
 	solver.Register(day, func() solver.PuzzleSolver {
-		return NewSolver()
+		return NewSolverWithCtx()
 	})
 }
 
@@ -33,22 +76,47 @@ func NewSolver() *PuzzleStruct {
 	return &PuzzleStruct{}
 }
 
+// Returns description of the puzzle
+func (p *PuzzleStruct) Metadata() solver.Metadata {
+	return metadata
+}
+
+// Returns summary of the parsed input
+func (p *PuzzleStruct) Describe() map[string]int64 {
+	return map[string]int64{"stones": int64(len(p.stones))}
+}
+
+// Returns how well the input fits, single stone is rather a number
+func (p *PuzzleStruct) Score() float64 {
+	if len(p.stones) < 2 {
+		return 0.3
+	}
+
+	return 0.8
+}
+
+// Sets the blinks parameter
+func (p *PuzzleStruct) SetParams(params solver.Params) error {
+	p.blinks = params.Int("blinks")
+
+	return nil
+}
+
 // Initializes the PuzzleStruct with input
 // Return nil on success
 func (p *PuzzleStruct) Init(reader io.Reader) error {
-	inputList, err := parseInput(bufio.NewScanner(reader))
+	stones, err := parseInput(reader)
 
 	if err != nil {
-		log.Print(err)
 		return err
 	}
 
-	if err := validateInput(inputList); err != nil {
-		log.Print(err)
+	if err := validateInput(stones); err != nil {
 		return err
 	}
 
-	p.l = inputList
+	p.stones = stones
+	p.blinking = newBlinking(stones)
 
 	return nil
 }
@@ -57,156 +125,238 @@ func (p *PuzzleStruct) Init(reader io.Reader) error {
 // Accepts part as parameter
 // Returns string containing the solution of the puzzle
 func (p *PuzzleStruct) Solve(part int) (string, error) {
+	blinks, err := p.blinksOf(part)
+
+	if err != nil {
+		return "", err
+	}
+
+	return Blink(p.stones, blinks).String(), nil
+}
+
+// Returns number of blinks of the part
+func (p *PuzzleStruct) blinksOf(part int) (int, error) {
+	var blinks int
+
 	switch part {
 	case 1:
-		sum := Blink(p.l, 25)
-		return strconv.Itoa(sum), nil
+		blinks = blinksPart1
 	case 2:
-		sum := Blink(p.l, 75)
-		return strconv.Itoa(sum), nil
+		blinks = blinksPart2
+	default:
+		return 0, fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
 	}
 
-	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
+	if p.blinks > 0 {
+		return p.blinks, nil
+	}
+
+	return blinks, nil
 }
 
-// Parses provided input
-// Returns parsed list
-func parseInput(sc *bufio.Scanner) (*list.List, error) {
-	var line string
-	var resultList = list.New()
+// Blinks once
+// Stops after the number of blinks of part 2
+// Returns serialised Step, ErrNoMoreSteps after the last blink
+func (p *PuzzleStruct) Next() (string, error) {
+	last, _ := p.blinksOf(2)
 
-	// expecting only 1 line
+	if p.blinking.round >= last {
+		return "", solver.ErrNoMoreSteps
+	}
 
-	for i := 0; sc.Scan(); i++ {
+	p.blinking.blink()
 
-		if i > 0 {
-			return nil, fmt.Errorf("%s multiline input: %w", day, solver.ErrInvalidInput)
-		}
+	step := p.blinking.step(last)
 
-		line = strings.TrimSpace(sc.Text())
-		fields := strings.Fields(line)
+	if step.Distinct <= maxListedStones {
+		step.Counts = p.blinking.counts()
+	}
 
-		for _, field := range fields {
-			n, err := strconv.Atoi(string(field))
-			if err != nil {
-				log.Print(err)
-				return nil, fmt.Errorf("%s unable to convert %s to int: %w", day, string(field), solver.ErrInvalidInput)
-			}
+	b, err := json.Marshal(step)
 
-			resultList.PushBack(n)
-		}
+	if err != nil {
+		return "", fmt.Errorf("%s unable to marshal step: %w", day, err)
 	}
 
-	if sc.Err() != nil {
-		return nil, fmt.Errorf("%s scan error %s: %w", day, sc.Err(), solver.ErrInvalidInput)
+	return string(b), nil
+}
+
+// Parses provided input
+// Returns values of the stones
+func parseInput(reader io.Reader) ([]int, error) {
+	// expecting only 1 line
+	line, err := parse.SingleLine(reader)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s %w", day, err)
 	}
 
-	return resultList, nil
+	fields := line.Fields()
+	stones := make([]int, 0, len(fields))
+
+	for _, f := range fields {
+		stone, err := f.Int()
 
+		if err != nil {
+			return nil, fmt.Errorf("%s %w", day, err)
+		}
+
+		if stone < 0 {
+			return nil, fmt.Errorf("%s %w", day, parse.Errorf(f.Line, f.Column, "non-negative number", f.Text, "negative stone"))
+		}
+
+		stones = append(stones, stone)
+	}
+
+	return stones, nil
 }
 
 // Validates parsed input
 // Returns nil in case of successfull validation
-func validateInput(l *list.List) error {
-	if l == nil {
-		return fmt.Errorf("%s empty input: %w", day, solver.ErrInvalidInput)
-	} else if l.Len() == 0 {
-		return fmt.Errorf("%s empty input: %w", day, solver.ErrInvalidInput)
+func validateInput(stones []int) error {
+	if len(stones) == 0 {
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "stones", "", "empty input"))
 	}
 
 	return nil
 }
 
-func Blink(l *list.List, cnt int) int {
+// Blinks cnt times
+// Returns number of stones, stones are blinked as big numbers once
+// their values or counts overflow int
+func Blink(stones []int, cnt int) *big.Int {
+	b, _ := blinkRounds(context.Background(), stones, cnt, nil)
 
-	var sum int
+	return b.total()
+}
 
-	var stack = list.New()
+// Returns the stones before the first blink
+func newBlinking(stones []int) blinking {
+	counts := make(map[int]int, len(stones))
 
-	type stackElem struct {
-		depth int
-		value int
+	for _, stone := range stones {
+		counts[stone]++
 	}
 
-	var memoizationMap = make(map[stackElem]int)
+	return blinking{stones: counts}
+}
 
-	var new, new1, new2, curr stackElem
+// Blinks cnt times, one round after another, checks ctx every round
+// Only stones of the current round are kept, fn receives the Step
+// without counts after every round, it may be nil
+// Returns stones after the last round
+func blinkRounds(ctx context.Context, stones []int, cnt int, fn func(Step)) (blinking, error) {
+	b := newBlinking(stones)
+
+	for b.round < cnt {
+		select {
+		case <-ctx.Done():
+			return blinking{}, solver.ErrTimeout
+		default:
+		}
+
+		b.blink()
 
-	for e := l.Front(); e != nil; e = e.Next() {
-		stack.PushBack(stackElem{depth: 0, value: e.Value.(int)})
+		if fn != nil {
+			fn(b.step(cnt))
+		}
 	}
 
-	for e := stack.Front(); e != nil; {
+	return b, nil
+}
 
-		curr = e.Value.(stackElem)
+// Blinks once, stones are promoted to large when they overflow int
+func (b *blinking) blink() {
+	if b.large == nil {
+		if next, ok := blinkRound(b.stones); ok {
+			b.stones = next
+			b.round++
+			return
+		}
 
-		// if value is memoized, drop the value
-		// if its original list, count it
-		if val, ok := memoizationMap[curr]; ok {
-			stack.Remove(e)
-			e = stack.Front()
+		b.large = promote(b.stones)
+		b.stones = nil
+	}
 
-			if curr.depth == 0 {
-				sum += val
-			}
-			continue
-		}
+	b.large = blinkRoundBig(b.large)
+	b.round++
+}
 
-		// full depth in
-		// memoize the value as 1
-		if curr.depth >= cnt {
-			memoizationMap[curr] = 1
-			stack.Remove(e)
-			e = stack.Front()
-			continue
-		}
+// Returns number of stones
+func (b *blinking) total() *big.Int {
+	if b.large != nil {
+		return b.large.total()
+	}
 
-		// not full depth in
-		// calculate the next value
-		if curr.value == 0 {
-			new = stackElem{depth: curr.depth + 1, value: 1}
-
-			// if calculated value is memoized, saved this value
-			if val, ok := memoizationMap[new]; ok {
-				memoizationMap[curr] = val
-				// if not then calculate the value
-			} else {
-				stack.PushFront(new)
-			}
-
-		} else if noOfDigits(curr.value)%2 == 0 {
-			n1, n2 := splitNumber(e.Value.(stackElem).value)
-			new1 = stackElem{depth: curr.depth + 1, value: n1}
-			new2 = stackElem{depth: curr.depth + 1, value: n2}
-
-			val1, ok1 := memoizationMap[new1]
-			val2, ok2 := memoizationMap[new2]
-
-			if !ok2 {
-				stack.PushFront(new2)
-			}
-
-			if !ok1 {
-				stack.PushFront(new1)
-			}
-
-			if ok1 && ok2 {
-				memoizationMap[curr] = val1 + val2
-			}
-		} else {
-			new = stackElem{depth: curr.depth + 1, value: curr.value * 2024}
-			if val, ok := memoizationMap[new]; ok {
-				memoizationMap[curr] = val
-			} else {
-				stack.PushFront(new)
-			}
+	var sum solver.Sum
+
+	for _, count := range b.stones {
+		sum.Add(count)
+	}
+
+	return sum.Big()
+}
+
+// Returns number of distinct stone values
+func (b *blinking) distinct() int {
+	if b.large != nil {
+		return len(b.large)
+	}
+
+	return len(b.stones)
+}
+
+// Returns counts of the stones by their decimal values
+func (b *blinking) counts() bigStones {
+	if b.large != nil {
+		return b.large
+	}
+
+	return promote(b.stones)
+}
+
+// Returns Step of the round without counts, last is the final round
+func (b *blinking) step(last int) Step {
+	return Step{
+		Round:    b.round,
+		Stones:   b.total(),
+		Distinct: b.distinct(),
+		Done:     b.round >= last,
+	}
+}
+
+// Applies one blink to stones stored as value -> count
+// Returns new map of stones, ok is false if values or counts overflow int
+func blinkRound(stones map[int]int) (map[int]int, bool) {
+	next := make(map[int]int, len(stones))
+
+	add := func(value, count int) bool {
+		n, ok := solver.AddInt(next[value], count)
+		next[value] = n
+		return ok
+	}
+
+	for value, count := range stones {
+		ok := true
+
+		switch {
+		case value == 0:
+			ok = add(1, count)
+		case noOfDigits(value)%2 == 0:
+			n1, n2 := splitNumber(value)
+			ok = add(n1, count) && add(n2, count)
+		default:
+			var v int
+			v, ok = solver.MulInt(value, 2024)
+			ok = ok && add(v, count)
 		}
 
-		// work from front
-		e = stack.Front()
+		if !ok {
+			return nil, false
+		}
 	}
 
-	return sum
+	return next, true
 }
 
 func noOfDigits(number int) int {
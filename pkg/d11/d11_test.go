@@ -1,9 +1,12 @@
 package d11
 
 import (
+	"advent2024/pkg/parse"
 	"advent2024/pkg/solver"
 	"context"
+	"encoding/json"
 	"errors"
+	"math"
 	"strings"
 	"testing"
 	"time"
@@ -124,3 +127,163 @@ func TestCtxTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestNext(t *testing.T) {
+	puzzle := NewSolverWithCtx()
+	_ = puzzle.InitCtx(context.Background(), strings.NewReader(inputTest))
+
+	var step Step
+	var err error
+	var got string
+
+	for got, err = puzzle.Next(context.Background()); err == nil; got, err = puzzle.Next(context.Background()) {
+		if err := json.Unmarshal([]byte(got), &step); err != nil {
+			t.Fatalf("unable to unmarshal step %s: %v", got, err)
+		}
+
+		if step.Round == 6 && step.Stones.Int64() != 22 {
+			t.Errorf("round 6: Got %d stones expected 22", step.Stones)
+		}
+
+		if step.Round == 25 && step.Stones.Int64() != 55312 {
+			t.Errorf("round 25: Got %d stones expected 55312", step.Stones)
+		}
+	}
+
+	if !errors.Is(err, solver.ErrNoMoreSteps) {
+		t.Errorf("Got %v expected %v", err, solver.ErrNoMoreSteps)
+	}
+
+	if !step.Done || step.Round != 75 {
+		t.Errorf("Got %+v expected 75 rounds", step)
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	cases := []struct {
+		name, input string
+		part        int
+		want        string
+	}{
+		{"value beyond int part 1", "1000000000000000000", 1, "14131"},
+		{"value beyond int part 2", "1000000000000000000", 2, "16958496280983"},
+		{"largest int", "9223372036854775807", 2, "27614245537889"},
+		{"mixed with small stones", "125 1000000000000000000 17", 1, "69443"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			puzzle := NewSolverWithCtx()
+			_ = puzzle.Init(strings.NewReader(c.input))
+
+			got, _ := puzzle.Solve(c.part)
+			gotCtx, _ := puzzle.SolveCtx(context.Background(), c.part)
+
+			if got != c.want || gotCtx != c.want {
+				t.Errorf("part %d: got %s and %s with ctx expected %s", c.part, got, gotCtx, c.want)
+			}
+		})
+	}
+
+	t.Run("counts beyond int", func(t *testing.T) {
+		if _, ok := blinkRound(map[int]int{0: math.MaxInt, 10: 1}); ok {
+			t.Errorf("got ok expected overflow of stones 1")
+		}
+	})
+
+	t.Run("promoted steps", func(t *testing.T) {
+		puzzle := NewSolverWithCtx()
+		_ = puzzle.InitCtx(context.Background(), strings.NewReader("1000000000000000000"))
+
+		got, err := puzzle.Next(context.Background())
+
+		if err != nil || got != `{"round":1,"stones":1,"distinct":1,"counts":{"2024000000000000000000":1},"done":false}` {
+			t.Errorf("got %s, %v expected stone 2024000000000000000000", got, err)
+		}
+	})
+
+	t.Run("negative stone", func(t *testing.T) {
+		err := NewSolver().Init(strings.NewReader("125 -17"))
+
+		var ie *parse.InputError
+
+		if !errors.As(err, &ie) || ie.Line != 1 || ie.Column != 5 || ie.Found != "-17" {
+			t.Errorf("got %v expected negative stone at line 1, column 5", err)
+		}
+	})
+}
+
+func TestParams(t *testing.T) {
+	params, err := metadata.ParseParams(map[string]string{"blinks": "6"})
+
+	if err != nil {
+		t.Fatalf("got %v expected nil", err)
+	}
+
+	puzzle := NewSolverWithCtx()
+	_ = puzzle.SetParams(params)
+	_ = puzzle.InitCtx(context.Background(), strings.NewReader(inputTest))
+
+	for part := 1; part <= 2; part++ {
+		got, _ := puzzle.Solve(part)
+		gotCtx, _ := puzzle.SolveCtx(context.Background(), part)
+
+		if got != "22" || gotCtx != "22" {
+			t.Errorf("part %d: got %s and %s with ctx expected 22", part, got, gotCtx)
+		}
+	}
+
+	steps := 0
+
+	for _, err := puzzle.Next(context.Background()); err == nil; _, err = puzzle.Next(context.Background()) {
+		steps++
+	}
+
+	if steps != 6 {
+		t.Errorf("got %d steps expected 6", steps)
+	}
+
+	if _, err := metadata.ParseParams(map[string]string{"blinks": "10001"}); !errors.Is(err, solver.ErrInvalidParam) {
+		t.Errorf("got %v expected %v", err, solver.ErrInvalidParam)
+	}
+}
+
+func TestBlinkRounds(t *testing.T) {
+	puzzle := NewSolver()
+	_ = puzzle.Init(strings.NewReader(inputTest))
+
+	rounds := 0
+
+	b, err := blinkRounds(context.Background(), puzzle.stones, 2000, func(step Step) {
+		rounds++
+
+		if step.Round != rounds {
+			t.Errorf("got round %d expected %d", step.Round, rounds)
+		}
+
+		if step.Round == 6 && step.Stones.Int64() != 22 {
+			t.Errorf("round 6: got %d stones expected 22", step.Stones)
+		}
+	})
+
+	if err != nil || rounds != 2000 {
+		t.Fatalf("got %d rounds, %v expected 2000 rounds", rounds, err)
+	}
+
+	got, _ := BlinkWithCtx(puzzle.stones, 2000, context.Background())
+
+	if got.Cmp(b.total()) != 0 || got.Cmp(Blink(puzzle.stones, 2000)) != 0 {
+		t.Errorf("got %s with ctx, expected %s", got, b.total())
+	}
+
+	if b.distinct() > 4000 {
+		t.Errorf("got %d distinct stones expected bounded number", b.distinct())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := BlinkWithCtx(puzzle.stones, 2000, ctx); !errors.Is(err, solver.ErrTimeout) {
+		t.Errorf("got %v expected %v", err, solver.ErrTimeout)
+	}
+}
@@ -0,0 +1,73 @@
+package d11
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Multiplier of stones with odd number of digits
+var big2024 = big.NewInt(2024)
+
+// Stones of any size stored as value -> count
+// Values are kept in decimal, their digits decide how the stone changes
+type bigStones map[string]*big.Int
+
+// Returns stones counted in int as stones of any size
+func promote(stones map[int]int) bigStones {
+	result := make(bigStones, len(stones))
+
+	for value, count := range stones {
+		result.add(strconv.Itoa(value), big.NewInt(int64(count)))
+	}
+
+	return result
+}
+
+// Adds count stones of the value
+func (s bigStones) add(value string, count *big.Int) {
+	if c, ok := s[value]; ok {
+		c.Add(c, count)
+		return
+	}
+
+	s[value] = new(big.Int).Set(count)
+}
+
+// Returns number of all stones
+func (s bigStones) total() *big.Int {
+	total := new(big.Int)
+
+	for _, count := range s {
+		total.Add(total, count)
+	}
+
+	return total
+}
+
+// Applies one blink to stones of any size like blinkRound
+// Returns new map of stones
+func blinkRoundBig(stones bigStones) bigStones {
+	next := make(bigStones, len(stones))
+
+	for value, count := range stones {
+		switch {
+		case value == "0":
+			next.add("1", count)
+		case len(value)%2 == 0:
+			right := strings.TrimLeft(value[len(value)/2:], "0")
+
+			if right == "" {
+				right = "0"
+			}
+
+			next.add(value[:len(value)/2], count)
+			next.add(right, count)
+		default:
+			v, _ := new(big.Int).SetString(value, 10)
+			next.add(v.Mul(v, big2024).String(), count)
+		}
+	}
+
+	return next
+}
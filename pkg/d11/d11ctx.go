@@ -2,11 +2,9 @@ package d11
 
 import (
 	"advent2024/pkg/solver"
-	"container/list"
 	"context"
-	"fmt"
 	"io"
-	"strconv"
+	"math/big"
 )
 
 // PuzzleStruct with Context
@@ -14,13 +12,6 @@ type PuzzleStructWithCtx struct {
 	PuzzleStruct
 }
 
-// Registers day wih the registry
-func init() {
-	solver.RegisterWithCtx(day, func() solver.PuzzleSolverWithCtx {
-		return NewSolverWithCtx()
-	})
-}
-
 // Constructor
 func NewSolverWithCtx() *PuzzleStructWithCtx {
 	return &PuzzleStructWithCtx{}
@@ -28,130 +19,78 @@ func NewSolverWithCtx() *PuzzleStructWithCtx {
 
 // Initializes the PuzzleStruct with input
 func (p *PuzzleStructWithCtx) InitCtx(ctx context.Context, reader io.Reader) error {
-	return p.PuzzleStruct.Init(reader)
+	return solver.InitWithCtx(ctx, reader, p.PuzzleStruct.Init)
+}
+
+// Reports progress of SolveCtx, one unit per blink
+func (p *PuzzleStructWithCtx) ReportsProgress() bool {
+	return true
 }
 
 // Solves the puzzle
 // Accepts part as parameter
 // Returns string containing the solution of the puzzle
 func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, error) {
-	switch part {
-	case 1:
-		sum, err := BlinkWithCtx(p.l, 25, ctx)
+	result, err := p.SolveResult(ctx, part)
 
-		if err != nil {
-			return "", err
-		}
+	if err != nil {
+		return "", err
+	}
 
-		return strconv.Itoa(sum), nil
-	case 2:
-		sum, err := BlinkWithCtx(p.l, 75, ctx)
+	return result.Output, nil
+}
 
-		if err != nil {
-			return "", err
-		}
+// Solves the puzzle
+// Result carries number of initial stones and the most distinct stone
+// values of a round
+func (p *PuzzleStructWithCtx) SolveResult(ctx context.Context, part int) (solver.Result, error) {
+	blinks, err := p.blinksOf(part)
 
-		return strconv.Itoa(sum), nil
+	if err != nil {
+		return solver.Result{}, err
 	}
 
-	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
-}
-
-func BlinkWithCtx(l *list.List, cnt int, ctx context.Context) (int, error) {
-	var sum int
+	distinct := 0
 
-	var stack = list.New()
+	b, err := blinkRounds(ctx, p.stones, blinks, func(step Step) {
+		distinct = max(distinct, step.Distinct)
+		solver.ReportProgress(ctx, step.Round, blinks)
+	})
 
-	type stackElem struct {
-		depth int
-		value int
+	if err != nil {
+		return solver.Result{}, err
 	}
 
-	var memoizationMap = make(map[stackElem]int)
+	sum := b.total()
 
-	var new, new1, new2, curr stackElem
+	return solver.Result{
+		Output: sum.String(),
+		Value:  solver.IntValue(sum),
+		Stats: map[string]int64{
+			"stones":   int64(len(p.stones)),
+			"distinct": int64(distinct),
+		},
+	}, nil
+}
 
-	for e := l.Front(); e != nil; e = e.Next() {
-		stack.PushBack(stackElem{depth: 0, value: e.Value.(int)})
+// Blinks once
+func (p *PuzzleStructWithCtx) Next(ctx context.Context) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", solver.ErrTimeout
+	default:
 	}
 
-	for i, e := 0, stack.Front(); e != nil; i++ {
-
-		if i%100000 == 0 {
-			select {
-			case <-ctx.Done():
-				return -1, solver.ErrTimeout
-			default:
-			}
-		}
-
-		curr = e.Value.(stackElem)
-
-		// if value is memoized, drop the value
-		// if its original list, count it
-		if val, ok := memoizationMap[curr]; ok {
-			stack.Remove(e)
-			e = stack.Front()
-
-			if curr.depth == 0 {
-				sum += val
-			}
-			continue
-		}
-
-		// full depth in
-		// memoize the value as 1
-		if curr.depth >= cnt {
-			memoizationMap[curr] = 1
-			stack.Remove(e)
-			e = stack.Front()
-			continue
-		}
-
-		// not full depth in
-		// calculate the next value
-		if curr.value == 0 {
-			new = stackElem{depth: curr.depth + 1, value: 1}
-
-			// if calculated value is memoized, saved this value
-			if val, ok := memoizationMap[new]; ok {
-				memoizationMap[curr] = val
-				// if not then calculate the value
-			} else {
-				stack.PushFront(new)
-			}
-
-		} else if noOfDigits(curr.value)%2 == 0 {
-			n1, n2 := splitNumber(e.Value.(stackElem).value)
-			new1 = stackElem{depth: curr.depth + 1, value: n1}
-			new2 = stackElem{depth: curr.depth + 1, value: n2}
-
-			val1, ok1 := memoizationMap[new1]
-			val2, ok2 := memoizationMap[new2]
-
-			if !ok2 {
-				stack.PushFront(new2)
-			}
-
-			if !ok1 {
-				stack.PushFront(new1)
-			}
-
-			if ok1 && ok2 {
-				memoizationMap[curr] = val1 + val2
-			}
-		} else {
-			new = stackElem{depth: curr.depth + 1, value: curr.value * 2024}
-			if val, ok := memoizationMap[new]; ok {
-				memoizationMap[curr] = val
-			} else {
-				stack.PushFront(new)
-			}
-		}
-
-		// work from front
-		e = stack.Front()
+	return p.PuzzleStruct.Next()
+}
+
+// Blinks cnt times like Blink, checks ctx every round
+func BlinkWithCtx(stones []int, cnt int, ctx context.Context) (*big.Int, error) {
+	b, err := blinkRounds(ctx, stones, cnt, nil)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return sum, nil
+	return b.total(), nil
 }
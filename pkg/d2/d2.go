@@ -1,20 +1,35 @@
 package d2
 
 import (
+	"advent2024/pkg/parse"
 	"advent2024/pkg/solver"
-	"bufio"
 	"fmt"
 	"io"
-	"log"
 	"strconv"
-	"strings"
 )
 
 var day = "d2"
 
+// Description of the puzzle
+var metadata = solver.Metadata{
+	Title:       "Red-Nosed Reports",
+	Description: "Counts safe reactor reports, levels have to change monotonically by 1 to 3.",
+	Parts: []solver.Part{
+		{Part: 1, Label: "Safe reports", ExampleAnswer: "2"},
+		{Part: 2, Label: "Safe reports with Problem Dampener", ExampleAnswer: "4"},
+	},
+	InputFormat: "Lines of whitespace separated integers, one report per line.",
+	Example: `7 6 4 2 1
+1 2 7 8 9
+9 7 6 2 1
+1 3 2 4 5
+8 6 4 4 1
+1 3 6 7 9`,
+}
+
 func init() {
 	solver.Register(day, func() solver.PuzzleSolver {
-		return NewSolver()
+		return NewSolverWithCtx()
 	})
 }
 
@@ -26,16 +41,42 @@ func NewSolver() *PuzzleStruct {
 	return &PuzzleStruct{}
 }
 
+// Returns description of the puzzle
+func (p *PuzzleStruct) Metadata() solver.Metadata {
+	return metadata
+}
+
+// Returns how well the input fits, reports usually have more than
+// two levels and there is more than one report
+func (p *PuzzleStruct) Score() float64 {
+	if len(*p.reports) < 2 {
+		return 0.2
+	}
+
+	levels := 0
+	for _, r := range *p.reports {
+		levels = max(levels, len(r))
+	}
+
+	switch {
+	case levels > 2:
+		return 0.8
+	case levels == 2:
+		return 0.4
+	}
+
+	return 0.2
+}
+
 func (p *PuzzleStruct) Init(reader io.Reader) error {
 
-	reports, err := parseInput(bufio.NewScanner(reader))
+	reports, err := parseInput(reader)
 
 	if err != nil {
 		return err
 	}
 
 	if err := validateInput(reports); err != nil {
-		log.Print(err)
 		return err
 	}
 
@@ -82,33 +123,12 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
 }
 
-func parseInput(sc *bufio.Scanner) (*[][]int, error) {
+func parseInput(reader io.Reader) (*[][]int, error) {
 
-	reports := make([][]int, 0)
+	reports, err := parse.Ints(reader, 0)
 
-	for sc.Scan() {
-
-		report := make([]int, 0)
-
-		if sc.Text() == "" {
-			continue
-		}
-
-		vs := strings.Fields(sc.Text())
-
-		for _, v := range vs {
-
-			i, err := strconv.Atoi(v)
-
-			if err != nil {
-				maxOutput := min(len(sc.Text()), 80)
-				return nil, fmt.Errorf("%s unable to parse \"%v\": %w", day, sc.Text()[:maxOutput], solver.ErrInvalidInput)
-			}
-
-			report = append(report, i)
-		}
-
-		reports = append(reports, report)
+	if err != nil {
+		return nil, fmt.Errorf("%s %w", day, err)
 	}
 
 	return &reports, nil
@@ -117,9 +137,9 @@ func parseInput(sc *bufio.Scanner) (*[][]int, error) {
 func validateInput(reports *[][]int) error {
 
 	if reports == nil {
-		return fmt.Errorf("%s empty records: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "records", "", "empty records"))
 	} else if len(*reports) == 0 {
-		return fmt.Errorf("%s empty records: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "records", "", "empty records"))
 	}
 	return nil
 }
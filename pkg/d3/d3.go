@@ -1,19 +1,31 @@
 package d3
 
 import (
+	"advent2024/pkg/parse"
 	"advent2024/pkg/solver"
 	"fmt"
 	"io"
-	"log"
 	"regexp"
 	"strconv"
 )
 
 var day = "d3"
 
+// Description of the puzzle
+var metadata = solver.Metadata{
+	Title:       "Mull It Over",
+	Description: "Executes mul instructions hidden in corrupted memory.",
+	Parts: []solver.Part{
+		{Part: 1, Label: "Sum of multiplications", ExampleAnswer: "161"},
+		{Part: 2, Label: "Sum of multiplications enabled by do() and don't()", ExampleAnswer: "48"},
+	},
+	InputFormat: "Arbitrary text containing mul(X,Y), do() and don't() instructions.",
+	Example:     `xmul(2,4)&mul[3,7]!^don't()_mul(5,5)+mul(32,64](mul(11,8)undo()?mul(8,5))`,
+}
+
 func init() {
 	solver.Register(day, func() solver.PuzzleSolver {
-		return NewSolver()
+		return NewSolverWithCtx()
 	})
 }
 
@@ -30,6 +42,32 @@ func NewSolver() *PuzzleStruct {
 	return &PuzzleStruct{entries: &[]puzzleEntry{}}
 }
 
+// Returns description of the puzzle
+func (p *PuzzleStruct) Metadata() solver.Metadata {
+	return metadata
+}
+
+// Returns how well the input fits, corrupted memory holds several mul
+// instructions, do and don't alone are rather text
+func (p *PuzzleStruct) Score() float64 {
+	muls := 0
+
+	for _, e := range *p.entries {
+		if e.instruction == "mul" {
+			muls++
+		}
+	}
+
+	switch {
+	case muls > 1:
+		return 0.9
+	case muls == 1:
+		return 0.5
+	}
+
+	return 0.1
+}
+
 func (p *PuzzleStruct) Init(reader io.Reader) error {
 
 	s, err := io.ReadAll(reader)
@@ -41,7 +79,6 @@ func (p *PuzzleStruct) Init(reader io.Reader) error {
 	p.entries = parseInput(string(s))
 
 	if err := validateInput(p.entries); err != nil {
-		log.Print(err)
 		return err
 	}
 
@@ -110,9 +147,9 @@ func parseInput(s string) *[]puzzleEntry {
 
 func validateInput(entries *[]puzzleEntry) error {
 	if entries == nil {
-		return fmt.Errorf("%s empty records: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "records", "", "empty records"))
 	} else if len(*entries) == 0 {
-		return fmt.Errorf("%s empty records: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "records", "", "empty records"))
 	}
 
 	return nil
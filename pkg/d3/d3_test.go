@@ -125,3 +125,28 @@ func TestCtxTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestScore(t *testing.T) {
+	cases := []struct {
+		name, input string
+		want        float64
+	}{
+		{"test input", inputTest, 0.9},
+		{"single mul", "mul(2,4)", 0.5},
+		{"do and don't only", "do()don't()", 0.1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			puzzle := NewSolver()
+
+			if err := puzzle.Init(strings.NewReader(c.input)); err != nil {
+				t.Fatalf("Got %v expected nil", err)
+			}
+
+			if got := puzzle.Score(); got != c.want {
+				t.Errorf("Got %v expected %v", got, c.want)
+			}
+		})
+	}
+}
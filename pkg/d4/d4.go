@@ -1,25 +1,51 @@
 package d4
 
 import (
+	"advent2024/pkg/grid"
 	"advent2024/pkg/solver"
-	"bufio"
 	"fmt"
 	"io"
-	"log"
 	"strconv"
 	"strings"
 )
 
 var day = "d4"
 
+// Description of the puzzle
+var metadata = solver.Metadata{
+	Title:       "Ceres Search",
+	Description: "Searches a word search grid.",
+	Parts: []solver.Part{
+		{Part: 1, Label: "Occurrences of XMAS", ExampleAnswer: "18"},
+		{Part: 2, Label: "Occurrences of X-MAS", ExampleAnswer: "9"},
+	},
+	Params: []solver.Param{
+		{Name: "word", Type: solver.ParamString, Description: "Word searched in part 1, its letters are allowed in the grid", Default: defaultWord, Min: 2, Max: 32},
+	},
+	InputFormat: "Rectangular grid of the letters X, M, A and S.",
+	Example: `MMMSXXMASM
+MSAMXMSMSA
+AMXSXMAAMM
+MSAMASMSMX
+XMASAMXAMM
+XXAMMXXAMA
+SMSMSASXSS
+SAXAMASAAA
+MAMMMXMMMM
+MXMXAXMASX`,
+}
+
+// Word searched in part 1 unless set by the parameter
+const defaultWord = "XMAS"
+
 type PuzzleStruct struct {
-	dx, dy int
-	input  [][]byte
+	input *grid.Grid[byte]
+	word  string
 }
 
 func init() {
 	solver.Register(day, func() solver.PuzzleSolver {
-		return NewSolver()
+		return NewSolverWithCtx()
 	})
 }
 
@@ -27,24 +53,67 @@ func NewSolver() *PuzzleStruct {
 	return &PuzzleStruct{}
 }
 
+// Returns description of the puzzle
+func (p *PuzzleStruct) Metadata() solver.Metadata {
+	return metadata
+}
+
+// Returns summary of the parsed input
+func (p *PuzzleStruct) Describe() map[string]int64 {
+	return map[string]int64{"width": int64(p.input.Width()), "height": int64(p.input.Height())}
+}
+
+// Returns how well the input fits, grid of more than one line holding
+// every letter of XMAS, grids missing a letter never hold the word
+func (p *PuzzleStruct) Score() float64 {
+	if p.input.Height() < 2 {
+		return 0.2
+	}
+
+	for _, b := range []byte(defaultWord) {
+		if len(p.input.Find(b)) == 0 {
+			return 0.3
+		}
+	}
+
+	return 1
+}
+
+// Sets the word parameter
+func (p *PuzzleStruct) SetParams(params solver.Params) error {
+	p.word = params.Text("word")
+
+	return nil
+}
+
+// Returns the word searched in part 1
+func (p *PuzzleStruct) searched() string {
+	if p.word == "" {
+		return defaultWord
+	}
+
+	return p.word
+}
+
 func (p *PuzzleStruct) Init(reader io.Reader) error {
-	input, err := parseInput(bufio.NewScanner(reader))
+	// letters of the searched word are allowed besides XMAS
+	allowed := defaultWord
+	word := p.searched()
 
-	if err != nil {
-		log.Print(err)
-		return err
+	for i := range len(word) {
+		if strings.IndexByte(allowed, word[i]) < 0 {
+			allowed += word[i : i+1]
+		}
 	}
 
-	if err := validateInput(input); err != nil {
-		log.Print(err)
+	input, err := grid.ParseBytes(reader, allowed)
+
+	if err != nil {
+		err = fmt.Errorf("%s %w", day, err)
 		return err
 	}
 
-	p.input = *input
-	p.dy = len(p.input)
-	if p.dy > 0 {
-		p.dx = len(p.input[0])
-	}
+	p.input = input
 
 	return nil
 }
@@ -54,10 +123,8 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 	case 1:
 		sum := 0
 
-		for y := 0; y < p.dy; y++ {
-			for x := 0; x < p.dx; x++ {
-				sum += p.xmas(x, y)
-			}
+		for _, pt := range p.input.Points() {
+			sum += p.xmas(pt)
 		}
 
 		return strconv.Itoa(sum), nil
@@ -65,11 +132,9 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 	case 2:
 		sum := 0
 
-		for y := 0; y < p.dy; y++ {
-			for x := 0; x < p.dx; x++ {
-				if p.xmasPart2(x, y) {
-					sum += 1
-				}
+		for _, pt := range p.input.Points() {
+			if p.xmasPart2(pt) {
+				sum += 1
 			}
 		}
 
@@ -79,131 +144,43 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
 }
 
-func (p *PuzzleStruct) xmas(x, y int) int {
+// Counts searched words, XMAS by default, starting at the point, in all 8 directions
+func (p *PuzzleStruct) xmas(pt grid.Point) int {
 
 	sum := 0
+	word := p.searched()
 
-	if p.is(x, y, 'X') {
-		neighbors := neighbors(x, y)
-		for _, nm := range neighbors {
-			if p.is(nm[0], nm[1], 'M') {
-				na := next(x, y, nm[0], nm[1])
-				if p.is(na[0], na[1], 'A') {
-					ns := next(nm[0], nm[1], na[0], na[1])
-					if p.is(ns[0], ns[1], 'S') {
-						sum += 1
-					}
-				}
-			}
-		}
-	}
+	if p.input.Is(pt, word[0]) {
+		for _, d := range grid.Offsets8 {
+			found := true
 
-	return sum
-}
+			for i := 1; i < len(word) && found; i++ {
+				found = p.input.Is(pt.Add(d.Mul(i)), word[i])
+			}
 
-func (p *PuzzleStruct) xmasPart2(x, y int) bool {
-	if p.is(x, y, 'A') {
-		nbs := neighbors(x, y)
-
-		tl := nbs[0]
-		tr := nbs[2]
-		bl := nbs[5]
-		br := nbs[7]
-
-		if (p.is(tl[0], tl[1], 'S')) &&
-			(p.is(tr[0], tr[1], 'S')) &&
-			(p.is(br[0], br[1], 'M')) &&
-			(p.is(bl[0], bl[1], 'M')) {
-			return true
-		} else if (p.is(tl[0], tl[1], 'M')) &&
-			(p.is(tr[0], tr[1], 'S')) &&
-			(p.is(br[0], br[1], 'S')) &&
-			(p.is(bl[0], bl[1], 'M')) {
-			return true
-		} else if (p.is(tl[0], tl[1], 'M')) &&
-			(p.is(tr[0], tr[1], 'M')) &&
-			(p.is(br[0], br[1], 'S')) &&
-			(p.is(bl[0], bl[1], 'S')) {
-			return true
-		} else if (p.is(tl[0], tl[1], 'S')) &&
-			(p.is(tr[0], tr[1], 'M')) &&
-			(p.is(br[0], br[1], 'M')) &&
-			(p.is(bl[0], bl[1], 'S')) {
-			return true
+			if found {
+				sum += 1
+			}
 		}
 	}
-	return false
-}
 
-func (p *PuzzleStruct) is(x, y int, c byte) bool {
-	if x >= 0 && x < p.dx && y >= 0 && y < p.dy {
-		return p.input[y][x] == c
-	}
-
-	return false
+	return sum
 }
 
-func neighbors(x, y int) [][2]int {
-	return [][2]int{
-		{x - 1, y - 1},
-		{x, y - 1},
-		{x + 1, y - 1},
-		{x - 1, y},
-		{x + 1, y},
-		{x - 1, y + 1},
-		{x, y + 1},
-		{x + 1, y + 1},
+// Checks for two MAS words crossing at the point
+func (p *PuzzleStruct) xmasPart2(pt grid.Point) bool {
+	if !p.input.Is(pt, 'A') {
+		return false
 	}
-}
-
-func next(x1, y1, x2, y2 int) [2]int {
-	dx := x2 - x1
-	dy := y2 - y1
-
-	return [2]int{x2 + dx, y2 + dy}
-}
-
-func parseInput(sc *bufio.Scanner) (*[][]byte, error) {
 
-	crossword := make([][]byte, 0)
+	nbs := pt.Neighbours8()
 
-	for sc.Scan() {
-		line_string := strings.TrimSpace(sc.Text())
-		if line_string == "" {
-			continue
-		}
-
-		line := []byte(line_string)
-		crossword = append(crossword, line)
-	}
-
-	return &crossword, nil
+	// top left to bottom right, top right to bottom left
+	return p.mas(nbs[0], nbs[7]) && p.mas(nbs[2], nbs[5])
 }
 
-func validateInput(field *[][]byte) error {
-	if field == nil {
-		return fmt.Errorf("%s empty records: %w", day, solver.ErrInvalidInput)
-	} else if len(*field) == 0 {
-		return fmt.Errorf("%s empty records: %w", day, solver.ErrInvalidInput)
-	}
-
-	rowLength := len((*field)[0])
-
-	for y := 0; y < len(*field); y++ {
-
-		if rowLength != len((*field)[y]) {
-			return fmt.Errorf("%s rows have unequal lengths: %w", day, solver.ErrInvalidInput)
-		}
-
-		for x := 0; x < rowLength; x++ {
-			switch (*field)[y][x] {
-			case 'X', 'M', 'A', 'S':
-				continue
-			default:
-				return fmt.Errorf("%s unknown character %s in input: %w", day, string((*field)[x][y]), solver.ErrInvalidInput)
-			}
-		}
-	}
-
-	return nil
+// Checks for M and S at the ends of the diagonal, in any order
+func (p *PuzzleStruct) mas(a, b grid.Point) bool {
+	return (p.input.Is(a, 'M') && p.input.Is(b, 'S')) ||
+		(p.input.Is(a, 'S') && p.input.Is(b, 'M'))
 }
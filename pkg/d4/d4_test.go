@@ -135,3 +135,73 @@ func TestCtxTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestParams(t *testing.T) {
+	cases := []struct {
+		name, input, word string
+		want              string
+	}{
+		{"default word", inputTest, "", "18"},
+		{"shorter word", inputTest, "MAS", "38"},
+		{"word with other letters", "SANTA\nXNXXX\nXXTXX", "SANTA", "1"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			values := map[string]string{}
+			if c.word != "" {
+				values["word"] = c.word
+			}
+
+			params, err := metadata.ParseParams(values)
+
+			if err != nil {
+				t.Fatalf("got %v expected nil", err)
+			}
+
+			puzzle := NewSolverWithCtx()
+			_ = puzzle.SetParams(params)
+
+			if err := puzzle.Init(strings.NewReader(c.input)); err != nil {
+				t.Fatalf("got %v expected nil", err)
+			}
+
+			got, _ := puzzle.SolveCtx(context.Background(), 1)
+
+			if got != c.want {
+				t.Errorf("got %s expected %s", got, c.want)
+			}
+		})
+	}
+
+	t.Run("letters of the default word only", func(t *testing.T) {
+		if err := NewSolver().Init(strings.NewReader("SANTA\nXNXXX")); !errors.Is(err, solver.ErrInvalidInput) {
+			t.Errorf("got %v expected %v", err, solver.ErrInvalidInput)
+		}
+	})
+}
+
+func TestScore(t *testing.T) {
+	cases := []struct {
+		name, input string
+		want        float64
+	}{
+		{"test input", inputTest, 1},
+		{"single line", "XMAS", 0.2},
+		{"missing letters", "XXMM\nXMXM", 0.3},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			puzzle := NewSolver()
+
+			if err := puzzle.Init(strings.NewReader(c.input)); err != nil {
+				t.Fatalf("Got %v expected nil", err)
+			}
+
+			if got := puzzle.Score(); got != c.want {
+				t.Errorf("Got %v expected %v", got, c.want)
+			}
+		})
+	}
+}
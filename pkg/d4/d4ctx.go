@@ -12,18 +12,12 @@ type PuzzleStructWithCtx struct {
 	PuzzleStruct
 }
 
-func init() {
-	solver.RegisterWithCtx(day, func() solver.PuzzleSolverWithCtx {
-		return NewSolverWithCtx()
-	})
-}
-
 func NewSolverWithCtx() *PuzzleStructWithCtx {
 	return &PuzzleStructWithCtx{}
 }
 
 func (p *PuzzleStructWithCtx) InitCtx(ctx context.Context, reader io.Reader) error {
-	return p.PuzzleStruct.Init(reader)
+	return solver.InitWithCtx(ctx, reader, p.PuzzleStruct.Init)
 }
 
 func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, error) {
@@ -31,9 +25,9 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 	case 1:
 		sum := 0
 
-		for y := 0; y < p.dy; y++ {
+		for i, pt := range p.input.Points() {
 
-			if y == 0 {
+			if i%p.input.Width() == 0 {
 				select {
 				case <-ctx.Done():
 					return "", solver.ErrTimeout
@@ -41,9 +35,7 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 				}
 			}
 
-			for x := 0; x < p.dx; x++ {
-				sum += p.xmas(x, y)
-			}
+			sum += p.xmas(pt)
 		}
 
 		return strconv.Itoa(sum), nil
@@ -51,18 +43,16 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 	case 2:
 		sum := 0
 
-		for y := 0; y < p.dy; y++ {
-			if y == 0 {
+		for i, pt := range p.input.Points() {
+			if i%p.input.Width() == 0 {
 				select {
 				case <-ctx.Done():
 					return "", solver.ErrTimeout
 				default:
 				}
 			}
-			for x := 0; x < p.dx; x++ {
-				if p.xmasPart2(x, y) {
-					sum += 1
-				}
+			if p.xmasPart2(pt) {
+				sum += 1
 			}
 		}
 
@@ -1,21 +1,58 @@
 package d5
 
 import (
+	"advent2024/pkg/parse"
 	"advent2024/pkg/solver"
-	"bufio"
 	"fmt"
 	"io"
-	"log"
 	"slices"
 	"strconv"
-	"strings"
 )
 
 var day = "d5"
 
+// Description of the puzzle
+var metadata = solver.Metadata{
+	Title:       "Print Queue",
+	Description: "Checks page updates against ordering rules.",
+	Parts: []solver.Part{
+		{Part: 1, Label: "Sum of middle pages of ordered updates", ExampleAnswer: "143"},
+		{Part: 2, Label: "Sum of middle pages of reordered updates", ExampleAnswer: "123"},
+	},
+	InputFormat: "Ordering rules X|Y, empty line, updates as comma separated page numbers.",
+	Example: `47|53
+97|13
+97|61
+97|47
+75|29
+61|13
+75|53
+29|13
+97|29
+53|29
+61|53
+97|53
+61|29
+47|13
+75|47
+97|75
+47|61
+75|61
+47|29
+75|13
+53|13
+
+75,47,61,53,29
+97,61,53,29,13
+75,29,13
+75,97,47,61,53
+61,13,29
+97,13,75,29,47`,
+}
+
 func init() {
 	solver.Register(day, func() solver.PuzzleSolver {
-		return NewSolver()
+		return NewSolverWithCtx()
 	})
 }
 
@@ -36,8 +73,37 @@ func NewSolver() *PuzzleStruct {
 	return &PuzzleStruct{}
 }
 
+// Returns description of the puzzle
+func (p *PuzzleStruct) Metadata() solver.Metadata {
+	return metadata
+}
+
+// Returns summary of the parsed input
+func (p *PuzzleStruct) Describe() map[string]int64 {
+	rules := 0
+	for _, r := range p.rules {
+		rules += len(r.after)
+	}
+
+	return map[string]int64{"rules": int64(rules), "updates": int64(len(p.updates))}
+}
+
+// Returns how well the input fits, updates have a middle page, the
+// score falls with the share of updates of even length
+func (p *PuzzleStruct) Score() float64 {
+	odd := 0
+
+	for _, u := range p.updates {
+		if len(u)%2 == 1 {
+			odd++
+		}
+	}
+
+	return 0.4 + 0.6*float64(odd)/float64(len(p.updates))
+}
+
 func (p *PuzzleStruct) Init(reader io.Reader) error {
-	input, err := parseInput(bufio.NewScanner(reader))
+	input, err := parseInput(reader)
 
 	if err != nil {
 		return err
@@ -47,7 +113,6 @@ func (p *PuzzleStruct) Init(reader io.Reader) error {
 	p.updates = input[1]
 
 	if err := validateInput(&p.rules, &p.updates); err != nil {
-		log.Print(err)
 		return err
 	}
 
@@ -70,8 +135,10 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 		sum := 0
 		for _, update := range p.updates {
 			if !slices.IsSortedFunc(update, p.sortFunc()) {
-				slices.SortFunc(update, p.sortFunc())
-				sum += update[len(update)/2]
+				// sort a copy, parsed updates are kept intact
+				sorted := slices.Clone(update)
+				slices.SortFunc(sorted, p.sortFunc())
+				sum += sorted[len(sorted)/2]
 			}
 		}
 		return strconv.Itoa(sum), nil
@@ -96,65 +163,39 @@ func (p *PuzzleStruct) sortFunc() func(a, b int) int {
 	}
 }
 
-type section int
+func parseInput(reader io.Reader) (*[2][][]int, error) {
 
-const (
-	RULES   section = 0
-	RECORDS section = 1
-)
-
-func parseInput(sc *bufio.Scanner) (*[2][][]int, error) {
+	sections, err := parse.Sections(reader, 2)
 
-	section := RULES
+	if err != nil {
+		return nil, fmt.Errorf("%s %w", day, err)
+	}
 
-	rules := make([][]int, 0)
-	updates := make([][]int, 0)
+	rules := make([][]int, 0, len(sections[0]))
+	updates := make([][]int, 0, len(sections[1]))
 
-	for sc.Scan() {
-		line_string := strings.TrimSpace(sc.Text())
+	for _, l := range sections[0] {
+		rule, err := l.SplitInts("|")
 
-		if line_string == "" {
-			section = RECORDS
-			continue
+		if err != nil {
+			return nil, fmt.Errorf("%s %w", day, err)
 		}
 
-		switch section {
-		case RULES:
-			rule_string := strings.Split(sc.Text(), "|")
-			if len(rule_string) != 2 {
-				maxOutput := min(len(sc.Text()), 80)
-				return nil, fmt.Errorf("%s unable to parse \"%v\": %w", day, sc.Text()[:maxOutput], solver.ErrInvalidInput)
-			}
-
-			rule := make([]int, 0)
-
-			for _, si := range rule_string {
-				v, err := strconv.Atoi(si)
-				if err != nil {
-					maxOutput := min(len(sc.Text()), 80)
-					return nil, fmt.Errorf("%s unable to parse \"%v\": %w", day, sc.Text()[:maxOutput], solver.ErrInvalidInput)
-				}
-				rule = append(rule, v)
-			}
-
-			rules = append(rules, rule)
-
-		case RECORDS:
-			update_string := strings.Split(sc.Text(), ",")
+		if len(rule) != 2 {
+			return nil, fmt.Errorf("%s %w", day, parse.Errorf(l.Num, 0, "rule X|Y", l.Text, "expected rule X|Y"))
+		}
 
-			update := make([]int, 0)
+		rules = append(rules, rule)
+	}
 
-			for _, si := range update_string {
-				v, err := strconv.Atoi(si)
-				if err != nil {
-					maxOutput := min(len(sc.Text()), 80)
-					return nil, fmt.Errorf("%s unable to parse \"%v\": %w", day, sc.Text()[:maxOutput], solver.ErrInvalidInput)
-				}
-				update = append(update, v)
-			}
+	for _, l := range sections[1] {
+		update, err := l.SplitInts(",")
 
-			updates = append(updates, update)
+		if err != nil {
+			return nil, fmt.Errorf("%s %w", day, err)
 		}
+
+		updates = append(updates, update)
 	}
 
 	var result [2][][]int
@@ -192,15 +233,15 @@ func processRules(parsedRules [][]int) map[int]Rules {
 
 func validateInput(rules *map[int]Rules, updates *[][]int) error {
 	if rules == nil {
-		return fmt.Errorf("%s empty rules: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "page ordering rules", "", "empty rules"))
 	} else if len(*rules) == 0 {
-		return fmt.Errorf("%s empty rules: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "page ordering rules", "", "empty rules"))
 	}
 
 	if updates == nil {
-		return fmt.Errorf("%s empty updates: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "updates", "", "empty updates"))
 	} else if len(*updates) == 0 {
-		return fmt.Errorf("%s empty updates: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "updates", "", "empty updates"))
 	}
 
 	return nil
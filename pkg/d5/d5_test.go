@@ -156,3 +156,27 @@ func TestCtxTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestScore(t *testing.T) {
+	cases := []struct {
+		name, input string
+		want        float64
+	}{
+		{"test input", inputTest, 1},
+		{"even update", "47|53\n\n47,53\n", 0.4},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			puzzle := NewSolver()
+
+			if err := puzzle.Init(strings.NewReader(c.input)); err != nil {
+				t.Fatalf("Got %v expected nil", err)
+			}
+
+			if got := puzzle.Score(); got != c.want {
+				t.Errorf("Got %v expected %v", got, c.want)
+			}
+		})
+	}
+}
@@ -13,18 +13,12 @@ type PuzzleStructWithCtx struct {
 	PuzzleStruct
 }
 
-func init() {
-	solver.RegisterWithCtx(day, func() solver.PuzzleSolverWithCtx {
-		return NewSolverWithCtx()
-	})
-}
-
 func NewSolverWithCtx() *PuzzleStructWithCtx {
 	return &PuzzleStructWithCtx{}
 }
 
 func (p *PuzzleStructWithCtx) InitCtx(ctx context.Context, reader io.Reader) error {
-	return p.PuzzleStruct.Init(reader)
+	return solver.InitWithCtx(ctx, reader, p.PuzzleStruct.Init)
 }
 
 func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, error) {
@@ -57,8 +51,10 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 				}
 			}
 			if !slices.IsSortedFunc(update, p.sortFunc()) {
-				slices.SortFunc(update, p.sortFunc())
-				sum += update[len(update)/2]
+				// sort a copy, parsed updates are kept intact
+				sorted := slices.Clone(update)
+				slices.SortFunc(sorted, p.sortFunc())
+				sum += sorted[len(sorted)/2]
 			}
 		}
 		return strconv.Itoa(sum), nil
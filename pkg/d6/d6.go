@@ -1,45 +1,96 @@
 package d6
 
 import (
+	"advent2024/pkg/grid"
+	"advent2024/pkg/parse"
 	"advent2024/pkg/solver"
-	"bufio"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"runtime"
 	"strconv"
-	"strings"
+	"sync"
 )
 
 var day = "d6"
 
+// Description of the puzzle
+var metadata = solver.Metadata{
+	Title:       "Guard Gallivant",
+	Description: "Simulates the walk of a guard turning right at obstacles.",
+	Parts: []solver.Part{
+		{Part: 1, Label: "Positions visited by the guard", ExampleAnswer: "41"},
+		{Part: 2, Label: "Obstacle positions causing a loop", ExampleAnswer: "6"},
+	},
+	InputFormat: "Rectangular grid of . (free) and # (obstacle) with one guard ^, >, v or <.",
+	Example: `....#.....
+.........#
+..........
+..#.......
+.......#..
+..........
+.#..^.....
+........#.
+#.........
+......#...`,
+}
+
 func init() {
 	solver.Register(day, func() solver.PuzzleSolver {
-		return NewSolver()
+		return NewSolverWithCtx()
 	})
 }
 
 type PuzzleStruct struct {
-	field [][]byte
+	field *grid.Grid[byte]
 	guard Guard
+	walk  walk
 }
 
-type Orientation int
+// Steps of the guard between checks of ctx
+const ctxCheckSteps = 1000
 
-const (
-	UP    Orientation = 0
-	RIGHT Orientation = 1
-	DOWN  Orientation = 2
-	LEFT  Orientation = 3
-)
+// Guard symbols ordered as grid.Directions
+var guardBytes = [4]byte{'^', '>', 'v', '<'}
 
-type Coords struct {
-	x, y int
+type Guard struct {
+	c       grid.Point
+	o       grid.Direction
+	visited map[grid.Point][4]bool
 }
 
-type Guard struct {
-	c       Coords
-	o       Orientation
-	visited map[Coords][4]bool
+// Position and orientation of the guard
+type state struct {
+	c grid.Point
+	o grid.Direction
+}
+
+// Obstacle outside of every field, leaving the field is a free step
+var noObstacle = grid.Point{X: -1, Y: -1}
+
+// Result of the search for obstacles causing a loop
+type loopSearch struct {
+	visited int
+	tried   int
+	loops   int
+}
+
+// State of the stepwise guard walk
+type walk struct {
+	guard Guard
+	step  int
+	done  bool
+}
+
+// Snapshot of the guard walk after a step
+type Step struct {
+	Step        int    `json:"step"`
+	X           int    `json:"x"`
+	Y           int    `json:"y"`
+	Orientation string `json:"orientation"`
+	Visited     int    `json:"visited"`
+	Done        bool   `json:"done"`
 }
 
 type NotInFieldError struct {
@@ -54,27 +105,48 @@ func NewSolver() *PuzzleStruct {
 	return &PuzzleStruct{}
 }
 
+// Returns description of the puzzle
+func (p *PuzzleStruct) Metadata() solver.Metadata {
+	return metadata
+}
+
+// Returns summary of the parsed input
+func (p *PuzzleStruct) Describe() map[string]int64 {
+	return map[string]int64{"width": int64(p.field.Width()), "height": int64(p.field.Height())}
+}
+
+// Returns how well the input fits, grid of more than one line with
+// obstacles the guard turns at
+func (p *PuzzleStruct) Score() float64 {
+	if p.field.Height() < 2 || len(p.field.Find('#')) == 0 {
+		return 0.3
+	}
+
+	return 1
+}
+
 func (p *PuzzleStruct) Init(reader io.Reader) error {
-	field, err := parseInput(bufio.NewScanner(reader))
+	field, err := grid.ParseBytes(reader, ".#^>v<")
 
 	if err != nil {
-		log.Print(err)
+		err = fmt.Errorf("%s %w", day, err)
 		return err
 	}
 
-	p.field = *field
+	gc, err := findGuard(field)
 
-	if err := validateInput(field); err != nil {
-		log.Print(err)
+	if err != nil {
 		return err
 	}
 
+	p.field = field
+
 	// checked above
-	gx, gy, _ := findGuard(field)
-	orientation, _ := toOrientation(p.field[gy][gx])
-	p.guard = NewGuard(gx, gy, orientation)
+	orientation, _ := toDirection(p.field.At(gc))
+	p.guard = NewGuard(gc, orientation)
+	p.walk = walk{guard: NewGuard(gc, orientation)}
 
-	p.field[gy][gx] = '.'
+	p.field.Set(gc, '.')
 
 	return nil
 }
@@ -84,141 +156,217 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 	case 1:
 		sum := 0
 
-		for p.guard.Move(&p.field) == nil {
+		// walk with a copy, initial guard is kept intact
+		guard := NewGuard(p.guard.c, p.guard.o)
+
+		for guard.Move(p.field) == nil {
 		}
 
-		sum = len(p.guard.visited)
+		sum = len(guard.visited)
 
 		return strconv.Itoa(sum), nil
 	case 2:
-		sum := 0
-
-		og := NewGuard(p.guard.c.x, p.guard.c.y, p.guard.o)
+		search, err := p.findLoops(context.Background())
 
-		for p.guard.Move(&p.field) == nil {
+		if err != nil {
+			return "", err
 		}
 
-		visited := p.guard.visited
+		return strconv.Itoa(search.loops), nil
+	}
 
-		for coord := range visited {
-			// get original guard
-			p.guard = NewGuard(og.c.x, og.c.y, og.o)
+	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
+}
 
-			// skip initial field
-			if p.guard.c == coord {
-				continue
-			}
+// Moves the guard by one step of the part 1 walk
+// Returns serialised Step, ErrNoMoreSteps after the guard left the field
+func (p *PuzzleStruct) Next() (string, error) {
+	if p.walk.done {
+		return "", solver.ErrNoMoreSteps
+	}
 
-			// put obstacle in place
-			p.field[coord.y][coord.x] = '#'
+	if err := p.walk.guard.Move(p.field); err != nil {
+		p.walk.done = true
+	}
 
-			var err error
-			// loop
-			for err = p.guard.Move(&p.field); err == nil; err = p.guard.Move(&p.field) {
-			}
+	p.walk.step++
 
-			switch err.(type) {
-			case LoopingError:
-				sum += 1
-			default:
-			}
+	step := Step{
+		Step:        p.walk.step,
+		X:           p.walk.guard.c.X,
+		Y:           p.walk.guard.c.Y,
+		Orientation: string(guardBytes[p.walk.guard.o]),
+		Visited:     len(p.walk.guard.visited),
+		Done:        p.walk.done,
+	}
 
-			// remove obstacle
-			p.field[coord.y][coord.x] = '.'
-		}
+	b, err := json.Marshal(step)
 
-		return strconv.Itoa(sum), nil
+	if err != nil {
+		return "", fmt.Errorf("%s unable to marshal step: %w", day, err)
 	}
 
-	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
+	return string(b), nil
 }
 
-func parseInput(sc *bufio.Scanner) (*[][]byte, error) {
+// Walks the guard until it leaves the field or loops
+// Returns state of the guard just before it first stepped into every
+// visited position except the initial one, error of ctx once it is done
+func (p *PuzzleStruct) firstEntries(ctx context.Context) (map[grid.Point]state, error) {
+	guard := NewGuard(p.guard.c, p.guard.o)
+	entries := make(map[grid.Point]state)
 
-	result := make([][]byte, 0)
+	for i := 0; ; i++ {
+		if i%ctxCheckSteps == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 
-	for sc.Scan() {
-		s := strings.TrimSpace(sc.Text())
-		bs := []byte(s)
+		before := state{guard.c, guard.o}
 
-		result = append(result, bs)
-	}
+		if guard.Move(p.field) != nil {
+			return entries, nil
+		}
+
+		if guard.c == before.c || guard.c == p.guard.c || !p.field.In(guard.c) {
+			continue
+		}
 
-	return &result, nil
+		if _, ok := entries[guard.c]; !ok {
+			entries[guard.c] = before
+		}
+	}
 }
 
-func validateInput(field *[][]byte) error {
-	if field == nil {
-		return fmt.Errorf("%s empty field: %w", day, solver.ErrInvalidInput)
-	} else if len(*field) == 0 {
-		return fmt.Errorf("%s empty field: %w", day, solver.ErrInvalidInput)
+// Counts obstacle positions on the walk of the guard causing a loop
+// Candidates are simulated concurrently by workers with their own guard,
+// the obstacle is an overlay of the shared field which is only read
+// A simulation starts just before the guard first steps into the
+// obstacle position, the walk up to there is not changed by the obstacle
+// Reports progress one unit per visited position
+func (p *PuzzleStruct) findLoops(ctx context.Context) (loopSearch, error) {
+	entries, err := p.firstEntries(ctx)
+
+	if err != nil {
+		return loopSearch{}, solver.ErrTimeout
 	}
 
-	rowLength := len((*field)[0])
-	guardFound := false
+	// initial position is not tried
+	search := loopSearch{visited: len(entries) + 1, tried: len(entries)}
 
-	for y := 0; y < len(*field); y++ {
+	solver.ReportProgress(ctx, 0, search.visited)
 
-		if rowLength != len((*field)[y]) {
-			return fmt.Errorf("%s rows have unequal lengths: %w", day, solver.ErrInvalidInput)
-		}
+	type candidate struct {
+		obstacle grid.Point
+		from     state
+	}
+
+	tasks := make(chan candidate)
+	looping := make(chan bool)
+
+	var wg sync.WaitGroup
 
-		for x := 0; x < rowLength; x++ {
-			switch (*field)[y][x] {
-			case '.', '#':
-				continue
-			case '^', '>', 'v', '<':
-				guardFound = true
-			default:
-				return fmt.Errorf("%s unknown character %s in input: %w", day, string((*field)[x][y]), solver.ErrInvalidInput)
+	for range runtime.NumCPU() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			guard := NewGuard(p.guard.c, p.guard.o)
+
+			for c := range tasks {
+				loop, err := guard.loops(ctx, p.field, c.obstacle, c.from)
+
+				if err != nil {
+					return
+				}
+
+				looping <- loop
 			}
-		}
+		}()
 	}
 
-	if !guardFound {
-		return fmt.Errorf("%s unable to find guard in input: %w", day, solver.ErrInvalidInput)
-	}
+	go func() {
+		wg.Wait()
+		close(looping)
+	}()
 
-	return nil
-}
+	go func() {
+		defer close(tasks)
 
-func findGuard(field *[][]byte) (int, int, error) {
-	for y, line := range *field {
-		for x, c := range line {
-			switch c {
-			case UP.Byte(), LEFT.Byte(), DOWN.Byte(), RIGHT.Byte():
-				return x, y, nil
-			default:
-				continue
+		for obstacle, from := range entries {
+			select {
+			case tasks <- candidate{obstacle, from}:
+			case <-ctx.Done():
+				return
 			}
 		}
+	}()
+
+	done := 1
+
+	for loop := range looping {
+		if loop {
+			search.loops++
+		}
+
+		done++
+		solver.ReportProgress(ctx, done, search.visited)
+	}
+
+	if ctx.Err() != nil {
+		return loopSearch{}, solver.ErrTimeout
 	}
 
-	return -1, -1, fmt.Errorf("%s guard not found in input: %w", day, solver.ErrInvalidInput)
+	solver.ReportProgress(ctx, done, search.visited)
+
+	return search, nil
 }
 
-func (o Orientation) Byte() byte {
-	bytes := []byte{'^', '>', 'v', '<'}
-	return bytes[o]
+// Returns position of the first guard in the field
+func findGuard(field *grid.Grid[byte]) (grid.Point, error) {
+	for _, c := range field.Points() {
+		if _, err := toDirection(field.At(c)); err == nil {
+			return c, nil
+		}
+	}
+
+	return grid.Point{}, fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "guard ^, >, v or <", "", "unable to find guard"))
 }
 
-func toOrientation(b byte) (Orientation, error) {
-	switch b {
-	case '^':
-		return UP, nil
-	case '>':
-		return RIGHT, nil
-	case 'v':
-		return DOWN, nil
-	case '<':
-		return LEFT, nil
+func toDirection(b byte) (grid.Direction, error) {
+	for d, g := range guardBytes {
+		if g == b {
+			return grid.Direction(d), nil
+		}
 	}
 
-	return UP, fmt.Errorf("unable to determine orientation %b", b)
+	return grid.Up, fmt.Errorf("unable to determine orientation %b", b)
 }
 
-func NewGuard(x, y int, o Orientation) Guard {
-	return Guard{Coords{x, y}, o, map[Coords][4]bool{}}
+func NewGuard(c grid.Point, o grid.Direction) Guard {
+	return Guard{c, o, map[grid.Point][4]bool{}}
+}
+
+// Walks the guard from the state with the obstacle added to the field
+// Visited positions are reset, the guard can be reused
+// Returns true if the guard loops, error of ctx once it is done, ctx is
+// checked before the walk and then every ctxCheckSteps steps
+func (g *Guard) loops(ctx context.Context, field *grid.Grid[byte], obstacle grid.Point, from state) (bool, error) {
+	g.c, g.o = from.c, from.o
+	clear(g.visited)
+
+	for i := 0; ; i++ {
+		if i%ctxCheckSteps == 0 && ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
+		switch g.MoveAround(field, obstacle).(type) {
+		case nil:
+		case LoopingError:
+			return true, nil
+		default:
+			return false, nil
+		}
+	}
 }
 
 func (e LoopingError) Error() string {
@@ -229,60 +377,45 @@ func (e NotInFieldError) Error() string {
 	return fmt.Sprintf("Guard out of field")
 }
 
-func (g *Guard) Move(field *[][]byte) error {
-	x, y := g.c.x, g.c.y
-	f := *field
-
-	if y < len(f) && y > -1 {
-		if x < len(f[y]) && x > -1 {
-			next := Coords{x, y}
-
-			switch g.o {
-			case UP:
-				next = Coords{x, y - 1}
-			case RIGHT:
-				next = Coords{x + 1, y}
-			case DOWN:
-				next = Coords{x, y + 1}
-			case LEFT:
-				next = Coords{x - 1, y}
-			}
+func (g *Guard) Move(field *grid.Grid[byte]) error {
+	return g.MoveAround(field, noObstacle)
+}
 
-			next_v := byte('.')
+// Moves the guard like Move, obstacle is one more # of the field
+func (g *Guard) MoveAround(field *grid.Grid[byte], obstacle grid.Point) error {
+	if !field.In(g.c) {
+		return NotInFieldError{}
+	}
 
-			if next.x > -1 && next.x < len(f[y]) && next.y > -1 && next.y < len(f) {
-				next_v = f[next.y][next.x]
-			}
+	next := g.c.Move(g.o)
 
-			switch next_v {
-			case '.':
-				visited := g.visited[g.c]
+	// leaving the field is a free step
+	nextV, ok := field.Get(next)
+	if !ok {
+		nextV = '.'
+	}
 
-				if visited[g.o] {
-					return LoopingError{}
-				}
+	if next == obstacle {
+		nextV = '#'
+	}
 
-				visited[g.o] = true
-				g.visited[g.c] = visited
-
-				g.c.x = next.x
-				g.c.y = next.y
-			case '#':
-				switch g.o {
-				case UP:
-					g.o = RIGHT
-				case RIGHT:
-					g.o = DOWN
-				case DOWN:
-					g.o = LEFT
-				case LEFT:
-					g.o = UP
-				}
-			}
+	// the same position and orientation always lead to the same step,
+	// turning is recorded as well so a boxed in guard is looping too
+	visited := g.visited[g.c]
 
-			return nil
-		}
+	if visited[g.o] {
+		return LoopingError{}
 	}
 
-	return NotInFieldError{}
+	visited[g.o] = true
+	g.visited[g.c] = visited
+
+	switch nextV {
+	case '.':
+		g.c = next
+	case '#':
+		g.o = g.o.TurnRight()
+	}
+
+	return nil
 }
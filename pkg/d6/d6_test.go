@@ -3,7 +3,11 @@ package d6
 import (
 	"advent2024/pkg/solver"
 	"context"
+	"encoding/json"
 	"errors"
+	"math/rand"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -163,3 +167,196 @@ func TestCtxTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestNext(t *testing.T) {
+	puzzle := NewSolverWithCtx()
+	_ = puzzle.InitCtx(context.Background(), strings.NewReader(inputTest))
+
+	var last Step
+	var err error
+	var got string
+
+	for got, err = puzzle.Next(context.Background()); err == nil; got, err = puzzle.Next(context.Background()) {
+		if err := json.Unmarshal([]byte(got), &last); err != nil {
+			t.Fatalf("unable to unmarshal step %s: %v", got, err)
+		}
+	}
+
+	if !errors.Is(err, solver.ErrNoMoreSteps) {
+		t.Errorf("Got %v expected %v", err, solver.ErrNoMoreSteps)
+	}
+
+	if !last.Done || last.Visited != 41 {
+		t.Errorf("Got %+v expected 41 visited", last)
+	}
+
+	// stepping doesn't influence solving
+	result, _ := puzzle.SolveCtx(context.Background(), 1)
+
+	if result != "41" {
+		t.Errorf("Got %s expected 41", result)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	var last solver.Progress
+	calls := 0
+
+	ctx := solver.WithProgress(context.Background(), func(p solver.Progress) {
+		last = p
+		calls++
+	})
+
+	puzzle := NewSolverWithCtx()
+	_ = puzzle.InitCtx(ctx, strings.NewReader(inputTest))
+	got, _ := puzzle.SolveCtx(ctx, 2)
+
+	if got != "6" {
+		t.Errorf("Got %s expected 6", got)
+	}
+
+	// one report per visited position and the final one
+	if calls != 42 || last.Done != 41 || last.Total != 41 {
+		t.Errorf("Got %d calls, last %+v expected 42 calls, last 41/41", calls, last)
+	}
+}
+
+func TestSolveResult(t *testing.T) {
+	cases := []struct {
+		name  string
+		part  int
+		want  int64
+		stats map[string]int64
+	}{
+		{"part 1", 1, 41, map[string]int64{"visited": 41}},
+		{"part 2", 2, 6, map[string]int64{"visited": 41, "tried": 40, "loops": 6}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			puzzle := NewSolverWithCtx()
+			_ = puzzle.InitCtx(context.Background(), strings.NewReader(inputTest))
+			got, _ := puzzle.SolveResult(context.Background(), c.part)
+
+			if got.Value != c.want || !reflect.DeepEqual(got.Stats, c.stats) {
+				t.Errorf("Got %v %v expected %v %v", got.Value, got.Stats, c.want, c.stats)
+			}
+		})
+	}
+}
+
+// Random field with about one obstacle per density cells
+func randomField(r *rand.Rand, width, height, density int) string {
+	var sb strings.Builder
+
+	gx, gy := r.Intn(width), r.Intn(height)
+
+	for y := range height {
+		for x := range width {
+			switch {
+			case x == gx && y == gy:
+				sb.WriteByte("^>v<"[r.Intn(4)])
+			case r.Intn(density) == 0:
+				sb.WriteByte('#')
+			default:
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+// Counts loops walking from the initial position with every obstacle
+func loopsFromStart(p *PuzzleStruct) int {
+	walked := NewGuard(p.guard.c, p.guard.o)
+
+	for walked.Move(p.field) == nil {
+	}
+
+	loops := 0
+
+	for coord := range walked.visited {
+		if coord == p.guard.c {
+			continue
+		}
+
+		guard := NewGuard(p.guard.c, p.guard.o)
+
+		if loop, _ := guard.loops(context.Background(), p.field, coord, state{p.guard.c, p.guard.o}); loop {
+			loops++
+		}
+	}
+
+	return loops
+}
+
+func TestLoopsFromFirstEntry(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+
+	for i := range 200 {
+		input := randomField(r, 5+r.Intn(20), 5+r.Intn(20), 3+r.Intn(8))
+
+		puzzle := NewSolver()
+
+		if err := puzzle.Init(strings.NewReader(input)); err != nil {
+			t.Fatalf("field %d: %v", i, err)
+		}
+
+		want := loopsFromStart(puzzle)
+		got, _ := puzzle.Solve(2)
+
+		if got != strconv.Itoa(want) {
+			t.Fatalf("field %d: got %s expected %d\n%s", i, got, want, input)
+		}
+	}
+}
+
+func TestWalksStopOnCancel(t *testing.T) {
+	puzzle := NewSolver()
+	_ = puzzle.Init(strings.NewReader(inputTest))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := puzzle.firstEntries(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("first entries: got %v expected %v", err, context.Canceled)
+	}
+
+	guard := NewGuard(puzzle.guard.c, puzzle.guard.o)
+	from := state{puzzle.guard.c, puzzle.guard.o}
+
+	if _, err := guard.loops(ctx, puzzle.field, noObstacle, from); !errors.Is(err, context.Canceled) {
+		t.Errorf("loops: got %v expected %v", err, context.Canceled)
+	}
+
+	if _, err := puzzle.findLoops(ctx); !errors.Is(err, solver.ErrTimeout) {
+		t.Errorf("find loops: got %v expected %v", err, solver.ErrTimeout)
+	}
+}
+
+func TestScore(t *testing.T) {
+	cases := []struct {
+		name, input string
+		want        float64
+	}{
+		{"test input", inputTest, 1},
+		{"no obstacles", "....\n.^..", 0.3},
+		{"single line", "..^#", 0.3},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			puzzle := NewSolver()
+
+			if err := puzzle.Init(strings.NewReader(c.input)); err != nil {
+				t.Fatalf("Got %v expected nil", err)
+			}
+
+			if got := puzzle.Score(); got != c.want {
+				t.Errorf("Got %v expected %v", got, c.want)
+			}
+		})
+	}
+}
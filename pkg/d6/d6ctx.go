@@ -12,84 +12,85 @@ type PuzzleStructWithCtx struct {
 	PuzzleStruct
 }
 
-func init() {
-	solver.RegisterWithCtx(day, func() solver.PuzzleSolverWithCtx {
-		return NewSolverWithCtx()
-	})
-}
-
 func NewSolverWithCtx() *PuzzleStructWithCtx {
 	return &PuzzleStructWithCtx{}
 }
 
 func (p *PuzzleStructWithCtx) InitCtx(ctx context.Context, reader io.Reader) error {
-	return p.PuzzleStruct.Init(reader)
+	return solver.InitWithCtx(ctx, reader, p.PuzzleStruct.Init)
+}
+
+// Reports progress of SolveCtx, part 2 one unit per tried obstacle
+func (p *PuzzleStructWithCtx) ReportsProgress() bool {
+	return true
 }
 
 func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, error) {
-	switch part {
-	case 1:
-		sum := 0
+	result, err := p.SolveResult(ctx, part)
 
-		for i := 0; p.guard.Move(&p.field) == nil; i++ {
-			if i%1000000 == 0 {
-				select {
-				case <-ctx.Done():
-					return "", solver.ErrTimeout
-				default:
-				}
-			}
-		}
+	if err != nil {
+		return "", err
+	}
 
-		sum = len(p.guard.visited)
+	return result.Output, nil
+}
 
-		return strconv.Itoa(sum), nil
-	case 2:
+// Solves the puzzle
+// Result carries statistics of the walk, visited positions,
+// part 2 also tried obstacles and loops found
+func (p *PuzzleStructWithCtx) SolveResult(ctx context.Context, part int) (solver.Result, error) {
+	switch part {
+	case 1:
 		sum := 0
 
-		og := NewGuard(p.guard.c.x, p.guard.c.y, p.guard.o)
+		// walk with a copy, initial guard is kept intact
+		guard := NewGuard(p.guard.c, p.guard.o)
 
-		for i := 0; p.guard.Move(&p.field) == nil; i++ {
-			if i%1000000 == 0 {
+		for i := 0; guard.Move(p.field) == nil; i++ {
+			if i%ctxCheckSteps == 0 {
 				select {
 				case <-ctx.Done():
-					return "", solver.ErrTimeout
+					return solver.Result{}, solver.ErrTimeout
 				default:
 				}
 			}
 		}
 
-		visited := p.guard.visited
-
-		for coord := range visited {
-			// get original guard
-			p.guard = NewGuard(og.c.x, og.c.y, og.o)
-
-			// skip initial field
-			if p.guard.c == coord {
-				continue
-			}
+		sum = len(guard.visited)
 
-			// put obstacle in place
-			p.field[coord.y][coord.x] = '#'
+		return solver.Result{
+			Output: strconv.Itoa(sum),
+			Value:  int64(sum),
+			Stats:  map[string]int64{"visited": int64(sum)},
+		}, nil
+	case 2:
+		search, err := p.findLoops(ctx)
 
-			var err error
-			// loop
-			for err = p.guard.Move(&p.field); err == nil; err = p.guard.Move(&p.field) {
-			}
+		if err != nil {
+			return solver.Result{}, err
+		}
 
-			switch err.(type) {
-			case LoopingError:
-				sum += 1
-			default:
-			}
+		return solver.Result{
+			Output: strconv.Itoa(search.loops),
+			Value:  int64(search.loops),
+			Stats: map[string]int64{
+				"visited": int64(search.visited),
+				"tried":   int64(search.tried),
+				"loops":   int64(search.loops),
+			},
+		}, nil
+	}
 
-			// remove obstacle
-			p.field[coord.y][coord.x] = '.'
-		}
+	return solver.Result{}, fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
+}
 
-		return strconv.Itoa(sum), nil
+// Moves the guard by one step of the part 1 walk
+func (p *PuzzleStructWithCtx) Next(ctx context.Context) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", solver.ErrTimeout
+	default:
 	}
 
-	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
+	return p.PuzzleStruct.Next()
 }
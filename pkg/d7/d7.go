@@ -1,21 +1,39 @@
 package d7
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"io"
-	"log"
-	"strconv"
-	"strings"
 
+	"advent2024/pkg/parse"
 	"advent2024/pkg/solver"
 )
 
 var day = "d7"
 
+// Description of the puzzle
+var metadata = solver.Metadata{
+	Title:       "Bridge Repair",
+	Description: "Finds operators making calibration equations true.",
+	Parts: []solver.Part{
+		{Part: 1, Label: "Calibration result with + and *", ExampleAnswer: "3749"},
+		{Part: 2, Label: "Calibration result with +, * and ||", ExampleAnswer: "11387"},
+	},
+	InputFormat: "Lines result: numbers, numbers separated by spaces.",
+	Example: `190: 10 19
+3267: 81 40 27
+83: 17 5
+156: 15 6
+7290: 6 8 6 15
+161011: 16 10 13
+192: 17 8 14
+21037: 9 7 18 13
+292: 11 6 16 20`,
+}
+
 func init() {
 	solver.Register(day, func() solver.PuzzleSolver {
-		return NewSolver()
+		return NewSolverWithCtx()
 	})
 }
 
@@ -31,16 +49,38 @@ func NewSolver() *PuzzleStruct {
 	return &PuzzleStruct{}
 }
 
+// Returns description of the puzzle
+func (p *PuzzleStruct) Metadata() solver.Metadata {
+	return metadata
+}
+
+// Returns summary of the parsed input
+func (p *PuzzleStruct) Describe() map[string]int64 {
+	return map[string]int64{"equations": int64(len(*p.equations))}
+}
+
+// Returns how well the input fits, equations combine at least two
+// numbers, the score falls with the share of single number equations
+func (p *PuzzleStruct) Score() float64 {
+	combined := 0
+
+	for _, e := range *p.equations {
+		if len(e.numbers) > 1 {
+			combined++
+		}
+	}
+
+	return 0.4 + 0.6*float64(combined)/float64(len(*p.equations))
+}
+
 func (p *PuzzleStruct) Init(reader io.Reader) error {
-	equations, err := parseInput(bufio.NewScanner(reader))
+	equations, err := parseInput(reader)
 
 	if err != nil {
-		log.Print(err)
 		return err
 	}
 
 	if err := validateInput(equations); err != nil {
-		log.Print(err)
 		return err
 	}
 
@@ -52,71 +92,41 @@ func (p *PuzzleStruct) Init(reader io.Reader) error {
 func (p *PuzzleStruct) Solve(part int) (string, error) {
 	switch part {
 	case 1:
-		sum := 0
+		var sum solver.Sum
 
 		for _, e := range *p.equations {
 			if solvable(e) {
-				sum += e.result
+				sum.Add(e.result)
 			}
 		}
-		return strconv.Itoa(sum), nil
+		return sum.String(), nil
 	case 2:
-		sum := 0
+		var sum solver.Sum
 
 		for _, e := range *p.equations {
 			if solvablePart2(e) {
-				sum += e.result
+				sum.Add(e.result)
 			}
 		}
 
-		return strconv.Itoa(sum), nil
+		return sum.String(), nil
 	}
 
 	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
 }
 
-func parseInput(sc *bufio.Scanner) (*[]Equation, error) {
-
-	input := make([]Equation, 0)
+func parseInput(reader io.Reader) (*[]Equation, error) {
 
-	for sc.Scan() {
+	lines, err := parse.KeyValues(reader)
 
-		if strings.TrimSpace(sc.Text()) == "" {
-			continue
-		}
-
-		result_numbers := strings.Split(strings.TrimSpace(sc.Text()), ":")
-
-		if len(result_numbers) != 2 {
-			maxOutput := min(len(sc.Text()), 80)
-			return nil, fmt.Errorf("%s unable to parse \"%v\": %w", day, sc.Text()[:maxOutput], solver.ErrInvalidInput)
-		}
-
-		result := strings.TrimSpace(result_numbers[0])
-		numbers := strings.Fields(result_numbers[1])
-
-		r, err := strconv.Atoi(result)
-
-		if err != nil {
-			maxOutput := min(len(sc.Text()), 80)
-			return nil, fmt.Errorf("%s unable to parse \"%v\": %w", day, sc.Text()[:maxOutput], solver.ErrInvalidInput)
-		}
-
-		ints := make([]int, len(numbers))
-
-		for i := range len(numbers) {
-			n, err := strconv.Atoi(numbers[i])
-
-			if err != nil {
-				maxOutput := min(len(sc.Text()), 80)
-				return nil, fmt.Errorf("%s unable to parse \"%v\": %w", day, sc.Text()[:maxOutput], solver.ErrInvalidInput)
-			}
-
-			ints[i] = n
-		}
+	if err != nil {
+		return nil, fmt.Errorf("%s %w", day, err)
+	}
 
-		input = append(input, Equation{result: r, numbers: ints})
+	input := make([]Equation, 0, len(lines))
 
+	for _, l := range lines {
+		input = append(input, Equation{result: l.Key, numbers: l.Values})
 	}
 
 	return &input, nil
@@ -124,83 +134,41 @@ func parseInput(sc *bufio.Scanner) (*[]Equation, error) {
 
 func validateInput(equations *[]Equation) error {
 	if equations == nil {
-		return fmt.Errorf("%s empty equations: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "equations", "", "empty equations"))
 	} else if len(*equations) == 0 {
-		return fmt.Errorf("%s empty equations: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "equations", "", "empty equations"))
 	}
 
 	return nil
 }
 
+// Checks the equation with add and mul, see solvableCtx
 func solvable(e Equation) bool {
-	numbers := e.numbers
-
-	if len(numbers) == 0 {
-		return false
-	}
-
-	if len(numbers) == 1 {
-		return false
-	}
-	return _solvable(e.result, numbers[0], numbers[1:])
-}
-
-func _solvable(res, acc int, nums []int) bool {
-
-	if res < acc {
-		return false
-	}
-
-	if len(nums) == 0 {
-		return res == acc
-	}
-
-	if len(nums) == 1 {
-		return _solvable(res, acc+nums[0], []int{}) || _solvable(res, acc*nums[0], []int{})
-	}
-
-	return _solvable(res, acc+nums[0], nums[1:]) || _solvable(res, acc*nums[0], nums[1:])
+	ok, _ := solvableCtx(context.Background(), e, false)
+	return ok
 }
 
+// Checks the equation with add, mul and concat, see solvableCtx
 func solvablePart2(e Equation) bool {
-	numbers := e.numbers
-
-	if len(numbers) == 0 {
-		return false
-	}
-
-	if len(numbers) == 1 {
-		return false
-	}
-	return _solvablePart2(e.result, numbers[0], numbers[1:])
+	ok, _ := solvableCtx(context.Background(), e, true)
+	return ok
 }
 
-func _concat(i, j int) int {
+// Concatenates digits of i and j, ok is false if the result overflows int
+func _concat(i, j int) (int, bool) {
 
 	result := i
 	c := j
+	ok := true
 
-	for c > 0 {
+	for c > 0 && ok {
 		c = c / 10
-		result *= 10
-	}
-
-	return result + j
-}
-
-func _solvablePart2(res, acc int, nums []int) bool {
-
-	if res < acc {
-		return false
-	}
-
-	if len(nums) == 0 {
-		return res == acc
+		result, ok = solver.MulInt(result, 10)
 	}
 
-	if len(nums) == 1 {
-		return _solvablePart2(res, acc+nums[0], []int{}) || _solvablePart2(res, acc*nums[0], []int{}) || _solvablePart2(res, _concat(acc, nums[0]), []int{})
+	if !ok {
+		return 0, false
 	}
 
-	return _solvablePart2(res, acc+nums[0], nums[1:]) || _solvablePart2(res, acc*nums[0], nums[1:]) || _solvablePart2(res, _concat(acc, nums[0]), nums[1:])
+	return solver.AddInt(result, j)
 }
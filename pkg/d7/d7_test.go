@@ -159,3 +159,54 @@ func TestCtxTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestOverflow(t *testing.T) {
+	cases := []struct {
+		name, input string
+		want        string
+	}{
+		{"product wrapping to the result", "4611686018427387904: 4611686018427387904 4 4611686018427387904", "0"},
+		{"concat wrapping below the result", "1844674407370955163: 1844674407370955161 9 614891469123651721", "0"},
+		{"sum beyond int", "6000000000000000000: 6000000000000000000 1\n6000000000000000000: 6000000000000000000 1", "12000000000000000000"},
+	}
+
+	for _, c := range cases {
+		for part := 1; part <= 2; part++ {
+			t.Run(c.name, func(t *testing.T) {
+				puzzle := NewSolverWithCtx()
+				_ = puzzle.Init(strings.NewReader(c.input))
+
+				got, _ := puzzle.Solve(part)
+				gotCtx, _ := puzzle.SolveCtx(context.Background(), part)
+
+				if got != c.want || gotCtx != c.want {
+					t.Errorf("part %d: got %s and %s with ctx expected %s", part, got, gotCtx, c.want)
+				}
+			})
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	cases := []struct {
+		name, input string
+		want        float64
+	}{
+		{"test input", inputTest, 1},
+		{"single numbers", "190: 190\n3: 3", 0.4},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			puzzle := NewSolver()
+
+			if err := puzzle.Init(strings.NewReader(c.input)); err != nil {
+				t.Fatalf("Got %v expected nil", err)
+			}
+
+			if got := puzzle.Score(); got != c.want {
+				t.Errorf("Got %v expected %v", got, c.want)
+			}
+		})
+	}
+}
@@ -5,33 +5,31 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"strconv"
 )
 
 type PuzzleStructWithCtx struct {
 	PuzzleStruct
 }
 
-func init() {
-	solver.RegisterWithCtx(day, func() solver.PuzzleSolverWithCtx {
-		return NewSolverWithCtx()
-	})
-}
-
 func NewSolverWithCtx() *PuzzleStructWithCtx {
 	return &PuzzleStructWithCtx{}
 }
 
 func (p *PuzzleStructWithCtx) InitCtx(ctx context.Context, reader io.Reader) error {
-	return p.PuzzleStruct.Init(reader)
+	return solver.InitWithCtx(ctx, reader, p.PuzzleStruct.Init)
+}
+
+// Reports progress of SolveCtx, one unit per equation
+func (p *PuzzleStructWithCtx) ReportsProgress() bool {
+	return true
 }
 
 func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, error) {
 	switch part {
 	case 1:
-		sum := 0
+		var sum solver.Sum
 
-		for _, e := range *p.equations {
+		for i, e := range *p.equations {
 
 			select {
 			case <-ctx.Done():
@@ -39,15 +37,23 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 			default:
 			}
 
-			if solvable(e) {
-				sum += e.result
+			ok, err := solvableCtx(ctx, e, false)
+
+			if err != nil {
+				return "", err
 			}
+
+			if ok {
+				sum.Add(e.result)
+			}
+
+			solver.ReportProgress(ctx, i+1, len(*p.equations))
 		}
-		return strconv.Itoa(sum), nil
+		return sum.String(), nil
 	case 2:
-		sum := 0
+		var sum solver.Sum
 
-		for _, e := range *p.equations {
+		for i, e := range *p.equations {
 
 			select {
 			case <-ctx.Done():
@@ -55,13 +61,73 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 			default:
 			}
 
-			if solvablePart2(e) {
-				sum += e.result
+			ok, err := solvableCtx(ctx, e, true)
+
+			if err != nil {
+				return "", err
+			}
+
+			if ok {
+				sum.Add(e.result)
 			}
+
+			solver.ReportProgress(ctx, i+1, len(*p.equations))
 		}
 
-		return strconv.Itoa(sum), nil
+		return sum.String(), nil
 	}
 
 	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
 }
+
+// Checks whether add, mul and with concat also concat operators give the result
+// Values overflowing int exceed any result, such operators are skipped
+// Gives up with ErrTimeout once ctx is done, long equations have
+// too many combinations of operators to check them between equations only
+func solvableCtx(ctx context.Context, e Equation, concat bool) (bool, error) {
+	if len(e.numbers) < 2 {
+		return false, nil
+	}
+
+	calls := 0
+	var err error
+
+	var check func(acc int, nums []int) bool
+
+	check = func(acc int, nums []int) bool {
+		calls++
+
+		if calls%100000 == 0 && ctx.Err() != nil {
+			err = solver.ErrTimeout
+		}
+
+		if err != nil || e.result < acc {
+			return false
+		}
+
+		if len(nums) == 0 {
+			return e.result == acc
+		}
+
+		// values overflowing int exceed the result
+		if sum, ok := solver.AddInt(acc, nums[0]); ok && check(sum, nums[1:]) {
+			return true
+		}
+
+		if product, ok := solver.MulInt(acc, nums[0]); ok && check(product, nums[1:]) {
+			return true
+		}
+
+		if !concat {
+			return false
+		}
+
+		c, ok := _concat(acc, nums[0])
+
+		return ok && check(c, nums[1:])
+	}
+
+	ok := check(e.numbers[0], e.numbers[1:])
+
+	return ok, err
+}
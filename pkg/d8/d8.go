@@ -1,56 +1,122 @@
 package d8
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"io"
-	"log"
 	"runtime"
 	"strconv"
-	"strings"
 	"sync"
+	"unicode"
 
+	"advent2024/pkg/grid"
 	"advent2024/pkg/solver"
 )
 
 var day = "d8"
 
+// Description of the puzzle
+var metadata = solver.Metadata{
+	Title:       "Resonant Collinearity",
+	Description: "Counts antinodes created by antennas of the same frequency.",
+	Parts: []solver.Part{
+		{Part: 1, Label: "Unique antinode locations", ExampleAnswer: "14"},
+		{Part: 2, Label: "Unique antinode locations with resonant harmonics", ExampleAnswer: "34", Variants: []string{"sequential", "parallel"}},
+	},
+	InputFormat: "Rectangular grid of . (empty) and antennas marked by a letter or digit.",
+	Example: `............
+........0...
+.....0......
+.......0....
+....0.......
+......A.....
+............
+............
+........A...
+.........A..
+............
+............`,
+}
+
 func init() {
-	solver.Register("d8", func() solver.PuzzleSolver {
-		return NewSolver()
+	solver.Register(day, func() solver.PuzzleSolver {
+		return NewSolverWithCtx()
 	})
 }
 
 type PuzzleStruct struct {
-	field    [][]byte
-	antennas map[byte][]Coords
+	field    *grid.Grid[byte]
+	antennas map[byte][]grid.Point
 }
 
-type Coords struct {
-	x, y int
-}
 type LineIter struct {
-	curr, delta Coords
+	curr, delta grid.Point
 }
 
 func NewSolver() *PuzzleStruct {
 	return &PuzzleStruct{}
 }
 
-func (p *PuzzleStruct) Init(reader io.Reader) error {
-	field, err := parseInput(bufio.NewScanner(reader))
+// Returns description of the puzzle
+func (p *PuzzleStruct) Metadata() solver.Metadata {
+	return metadata
+}
 
-	if err != nil {
-		log.Print(err)
-		return err
+// Returns summary of the parsed input
+func (p *PuzzleStruct) Describe() map[string]int64 {
+	antennas := 0
+	for _, a := range p.antennas {
+		antennas += len(a)
+	}
+
+	return map[string]int64{
+		"width":       int64(p.field.Width()),
+		"height":      int64(p.field.Height()),
+		"frequencies": int64(len(p.antennas)),
+		"antennas":    int64(antennas),
+	}
+}
+
+// Returns how well the input fits, any grid parses, antennas are
+// letters and digits on the empty '.' field
+// Grids without a pair of antennas of the same frequency have no
+// antinodes, they score 0 like grids of other symbols
+func (p *PuzzleStruct) Score() float64 {
+	pairs := false
+
+	for _, a := range p.antennas {
+		if len(a) > 1 {
+			pairs = true
+			break
+		}
+	}
+
+	if !pairs {
+		return 0
+	}
+
+	if len(p.field.Find('.')) == 0 {
+		return 0
+	}
+
+	for a := range p.antennas {
+		if !unicode.IsLetter(rune(a)) && !unicode.IsDigit(rune(a)) {
+			return 0
+		}
 	}
 
-	if err := validateInput(field); err != nil {
-		log.Print(err)
+	return 0.7
+}
+
+func (p *PuzzleStruct) Init(reader io.Reader) error {
+	field, err := grid.ParseBytes(reader, "")
+
+	if err != nil {
+		err = fmt.Errorf("%s %w", day, err)
 		return err
 	}
 
-	p.field = *field
+	p.field = field
 	p.findAntennas()
 
 	return nil
@@ -61,7 +127,7 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 	case 1:
 		sum := 0
 
-		antinodesMap := make(map[Coords]struct{})
+		antinodesMap := make(map[grid.Point]struct{})
 
 		for freq := range p.antennas {
 			antennas := p.antennas[freq]
@@ -70,7 +136,7 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 					antinodes := FindAntinodes(antennas[a1], antennas[a2])
 
 					for _, an := range antinodes {
-						if p.inField(an) {
+						if p.field.In(an) {
 							antinodesMap[an] = struct{}{}
 						}
 					}
@@ -85,7 +151,7 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 
 		sum := 0
 
-		antinodesMap := make(map[Coords]struct{})
+		antinodesMap := make(map[grid.Point]struct{})
 
 		for freq := range p.antennas {
 			// time.Sleep(time.Second / 1000)
@@ -97,7 +163,7 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 					iter := NewLineIter(antennas[a1], antennas[a2])
 
 					for an, ok := iter.Next(); ok; an, ok = iter.Next() {
-						if !p.inField(an) {
+						if !p.field.In(an) {
 							break
 						}
 						antinodesMap[an] = struct{}{}
@@ -107,7 +173,7 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 					iter = NewLineIter(antennas[a2], antennas[a1])
 
 					for an, ok := iter.Next(); ok; an, ok = iter.Next() {
-						if !p.inField(an) {
+						if !p.field.In(an) {
 							break
 						}
 						antinodesMap[an] = struct{}{}
@@ -120,174 +186,113 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 
 		return strconv.Itoa(sum), nil
 
-	case 21:
+	}
 
-		// Rerwite using Go Routines
+	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
+}
 
-		sum := 0
-		antinodesMap := make(map[Coords]struct{})
-
-		tasks := make(chan byte, 10)
-		results := make(chan Coords, 100)
-
-		var wg sync.WaitGroup
-
-		numWorkers := runtime.NumCPU()
-
-		for i := 0; i < numWorkers; i++ {
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				for t := range tasks {
-					// time.Sleep(time.Second / 1000)
-					freq := t
-					antennas := p.antennas[freq]
-					for a1 := 0; a1 < len(antennas); a1++ {
-						for a2 := a1 + 1; a2 < len(antennas); a2++ {
-
-							// line in one direction
-							iter := NewLineIter(antennas[a1], antennas[a2])
-
-							for an, ok := iter.Next(); ok; an, ok = iter.Next() {
-								if !p.inField(an) {
-									break
-								}
-								results <- an
+// Counts antinodes of part 2 with a worker per CPU, a frequency per task
+// Workers stop once ctx is done
+func (p *PuzzleStruct) parallelAntinodes(ctx context.Context) (int, error) {
+	antinodesMap := make(map[grid.Point]struct{})
+
+	tasks := make(chan byte, 10)
+	results := make(chan grid.Point, 100)
+
+	var wg sync.WaitGroup
+
+	numWorkers := runtime.NumCPU()
+
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range tasks {
+				freq := t
+				antennas := p.antennas[freq]
+				for a1 := 0; a1 < len(antennas) && ctx.Err() == nil; a1++ {
+					for a2 := a1 + 1; a2 < len(antennas); a2++ {
+
+						// line in one direction
+						iter := NewLineIter(antennas[a1], antennas[a2])
+
+						for an, ok := iter.Next(); ok; an, ok = iter.Next() {
+							if !p.field.In(an) {
+								break
 							}
+							results <- an
+						}
 
-							// line in the other direction
-							iter = NewLineIter(antennas[a2], antennas[a1])
+						// line in the other direction
+						iter = NewLineIter(antennas[a2], antennas[a1])
 
-							for an, ok := iter.Next(); ok; an, ok = iter.Next() {
-								if !p.inField(an) {
-									break
-								}
-								results <- an
+						for an, ok := iter.Next(); ok; an, ok = iter.Next() {
+							if !p.field.In(an) {
+								break
 							}
+							results <- an
 						}
 					}
-
 				}
-			}()
-		}
-
-		go func() {
-			wg.Wait()
-			close(results)
-		}()
 
-		go func() {
-			for t := range p.antennas {
-				tasks <- t
 			}
-			close(tasks)
 		}()
-
-		for an := range results {
-			antinodesMap[an] = struct{}{}
-		}
-
-		sum = len(antinodesMap)
-
-		return strconv.Itoa(sum), nil
 	}
 
-	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
-}
-
-func parseInput(sc *bufio.Scanner) (*[][]byte, error) {
-
-	result := make([][]byte, 0)
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
 
-	for sc.Scan() {
-		s := strings.TrimSpace(sc.Text())
-		bs := []byte(s)
-
-		result = append(result, bs)
-	}
+	go func() {
+		defer close(tasks)
 
-	return &result, nil
-}
+		for t := range p.antennas {
+			select {
+			case tasks <- t:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
 
-func validateInput(field *[][]byte) error {
-	if field == nil {
-		return fmt.Errorf("%s empty records: %w", day, solver.ErrInvalidInput)
-	} else if len(*field) == 0 {
-		return fmt.Errorf("%s empty records: %w", day, solver.ErrInvalidInput)
+	for an := range results {
+		antinodesMap[an] = struct{}{}
 	}
 
-	rowLength := len((*field)[0])
-
-	for y := 0; y < len(*field); y++ {
-		if rowLength != len((*field)[y]) {
-			return fmt.Errorf("%s rows have unequal lengths: %w", day, solver.ErrInvalidInput)
-		}
+	if ctx.Err() != nil {
+		return 0, solver.ErrTimeout
 	}
 
-	return nil
+	return len(antinodesMap), nil
 }
 
 func (p *PuzzleStruct) findAntennas() {
 
-	p.antennas = make(map[byte][]Coords)
+	p.antennas = make(map[byte][]grid.Point)
 
-	for y := range len(p.field) {
-		for x := range p.field[y] {
-			c := p.field[y][x]
-			if c != '.' {
-				v, ok := p.antennas[c]
-				if !ok {
-					p.antennas[c] = make([]Coords, 0)
-				}
-
-				p.antennas[c] = append(v, Coords{x: x, y: y})
-			}
+	for _, c := range p.field.Points() {
+		if v := p.field.At(c); v != '.' {
+			p.antennas[v] = append(p.antennas[v], c)
 		}
 	}
 }
 
-func (p *PuzzleStruct) inField(c Coords) bool {
-
-	if c.y >= 0 && c.y < len(p.field) {
-		if c.x >= 0 && c.x < len(p.field[c.y]) {
-			return true
-		}
-	}
-
-	return false
-}
+func FindAntinodes(this, other grid.Point) [2]grid.Point {
 
-func FindAntinodes(this, other Coords) [2]Coords {
+	delta := this.Sub(other)
 
-	delta := Sub(this, other)
-
-	a1 := Add(this, delta)
-	a2 := Add(other, Invert(delta))
-
-	return [2]Coords{a1, a2}
-}
-
-func Add(this, other Coords) Coords {
-	return Coords{x: this.x + other.x, y: this.y + other.y}
-}
-
-func Sub(this, other Coords) Coords {
-	return Coords{x: this.x - other.x, y: this.y - other.y}
-}
-
-func Eq(this, other Coords) bool {
-	return this.x == other.x && this.y == other.y
-}
+	a1 := this.Add(delta)
+	a2 := other.Add(delta.Neg())
 
-func Invert(this Coords) Coords {
-	return Coords{x: -this.x, y: -this.y}
+	return [2]grid.Point{a1, a2}
 }
 
-func NewLineIter(this, other Coords) LineIter {
-	return LineIter{curr: this, delta: Sub(other, this)}
+func NewLineIter(this, other grid.Point) LineIter {
+	return LineIter{curr: this, delta: other.Sub(this)}
 }
 
-func (i *LineIter) Next() (Coords, bool) {
-	i.curr = Add(i.curr, i.delta)
+func (i *LineIter) Next() (grid.Point, bool) {
+	i.curr = i.curr.Add(i.delta)
 	return i.curr, true
 }
@@ -1,6 +1,7 @@
 package d8
 
 import (
+	"advent2024/pkg/grid"
 	"advent2024/pkg/solver"
 	"context"
 	"errors"
@@ -32,7 +33,6 @@ func TestValid(t *testing.T) {
 	}{
 		{"test input part 1", inputTest, 1, "14"},
 		{"test input part 2", inputTest, 2, "34"},
-		{"test input part 21", inputTest, 21, "34"},
 	}
 
 	for _, c := range cases {
@@ -97,23 +97,47 @@ func TestAntinodes(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		this := Coords{x: tt.thisx, y: tt.thisy}
-		other := Coords{x: tt.otherx, y: tt.othery}
+		this := grid.Point{X: tt.thisx, Y: tt.thisy}
+		other := grid.Point{X: tt.otherx, Y: tt.othery}
 
-		want1 := Coords{x: tt.a1x, y: tt.a1y}
-		want2 := Coords{x: tt.a2x, y: tt.a2y}
+		want1 := grid.Point{X: tt.a1x, Y: tt.a1y}
+		want2 := grid.Point{X: tt.a2x, Y: tt.a2y}
 
 		as := FindAntinodes(this, other)
 
 		got1 := as[0]
 		got2 := as[1]
 
-		if !Eq(want1, got1) || !Eq(want2, got2) {
+		if want1 != got1 || want2 != got2 {
 			t.Errorf("Got %v %v expected %v %v", got1, got2, want1, want2)
 		}
 	}
 }
 
+func TestScore(t *testing.T) {
+	cases := []struct {
+		name, input string
+		want        float64
+	}{
+		{"test input", inputTest, 0.7},
+		{"no antennas", "....\n....", 0},
+		{"single antennas", "a...\n..b.", 0},
+		{"pair without empty field", "aa\naa", 0},
+		{"symbols", "#.#\n.^.", 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			puzzle := NewSolver()
+			_ = puzzle.Init(strings.NewReader(c.input))
+
+			if got := puzzle.Score(); got != c.want {
+				t.Errorf("Got %v expected %v", got, c.want)
+			}
+		})
+	}
+}
+
 func BenchmarkPart2(b *testing.B) {
 	input := `............s...............1.....................
 ......................E......3.....S..............
@@ -230,11 +254,11 @@ l.....w...........q..6............V...............`
 
 	b.ReportAllocs()
 
-	puzzle := NewSolver()
+	puzzle := NewSolverWithCtx()
 	_ = puzzle.Init(strings.NewReader(input))
 
 	for i := 0; i < b.N; i++ {
-		_, _ = puzzle.Solve(21)
+		_, _ = puzzle.SolveVariant(context.Background(), 2, "parallel")
 	}
 }
 
@@ -290,3 +314,30 @@ func TestCtxTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestVariants(t *testing.T) {
+	cases := []struct {
+		name    string
+		part    int
+		variant string
+		want    string
+		err     error
+	}{
+		{"part 2 sequential", 2, "sequential", "34", nil},
+		{"part 2 parallel", 2, "parallel", "34", nil},
+		{"part 2 unknown", 2, "quantum", "", solver.ErrUnknownVariant},
+		{"part 1 has none", 1, "parallel", "", solver.ErrUnknownVariant},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			puzzle := NewSolverWithCtx()
+			_ = puzzle.InitCtx(context.Background(), strings.NewReader(inputTest))
+			got, err := puzzle.SolveVariant(context.Background(), c.part, c.variant)
+
+			if got != c.want || !errors.Is(err, c.err) {
+				t.Errorf("Got %s, %v expected %s, %v", got, err, c.want, c.err)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package d8
 
 import (
+	"advent2024/pkg/grid"
 	"advent2024/pkg/solver"
 	"context"
 	"fmt"
@@ -12,18 +13,12 @@ type PuzzleStructWithCtx struct {
 	PuzzleStruct
 }
 
-func init() {
-	solver.RegisterWithCtx(day, func() solver.PuzzleSolverWithCtx {
-		return NewSolverWithCtx()
-	})
-}
-
 func NewSolverWithCtx() *PuzzleStructWithCtx {
 	return &PuzzleStructWithCtx{}
 }
 
 func (p *PuzzleStructWithCtx) InitCtx(ctx context.Context, reader io.Reader) error {
-	return p.PuzzleStruct.Init(reader)
+	return solver.InitWithCtx(ctx, reader, p.PuzzleStruct.Init)
 }
 
 func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, error) {
@@ -31,7 +26,7 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 	case 1:
 		sum := 0
 
-		antinodesMap := make(map[Coords]struct{})
+		antinodesMap := make(map[grid.Point]struct{})
 
 		for freq := range p.antennas {
 
@@ -43,11 +38,15 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 
 			antennas := p.antennas[freq]
 			for a1 := 0; a1 < len(antennas); a1++ {
+				if ctx.Err() != nil {
+					return "", solver.ErrTimeout
+				}
+
 				for a2 := a1 + 1; a2 < len(antennas); a2++ {
 					antinodes := FindAntinodes(antennas[a1], antennas[a2])
 
 					for _, an := range antinodes {
-						if p.inField(an) {
+						if p.field.In(an) {
 							antinodesMap[an] = struct{}{}
 						}
 					}
@@ -62,7 +61,7 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 
 		sum := 0
 
-		antinodesMap := make(map[Coords]struct{})
+		antinodesMap := make(map[grid.Point]struct{})
 
 		for freq := range p.antennas {
 
@@ -74,13 +73,17 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 
 			antennas := p.antennas[freq]
 			for a1 := 0; a1 < len(antennas); a1++ {
+				if ctx.Err() != nil {
+					return "", solver.ErrTimeout
+				}
+
 				for a2 := a1 + 1; a2 < len(antennas); a2++ {
 
 					// line in one direction
 					iter := NewLineIter(antennas[a1], antennas[a2])
 
 					for an, ok := iter.Next(); ok; an, ok = iter.Next() {
-						if !p.inField(an) {
+						if !p.field.In(an) {
 							break
 						}
 						antinodesMap[an] = struct{}{}
@@ -90,7 +93,7 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 					iter = NewLineIter(antennas[a2], antennas[a1])
 
 					for an, ok := iter.Next(); ok; an, ok = iter.Next() {
-						if !p.inField(an) {
+						if !p.field.In(an) {
 							break
 						}
 						antinodesMap[an] = struct{}{}
@@ -106,3 +109,22 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 
 	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
 }
+
+// Solves the part with the variant, part 2 sequential or with a worker
+// per CPU in parallel
+func (p *PuzzleStructWithCtx) SolveVariant(ctx context.Context, part int, variant string) (string, error) {
+	switch {
+	case part == 2 && variant == "sequential":
+		return p.SolveCtx(ctx, part)
+	case part == 2 && variant == "parallel":
+		sum, err := p.parallelAntinodes(ctx)
+
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.Itoa(sum), nil
+	}
+
+	return "", fmt.Errorf("%s part %d unknown variant %s: %w", day, part, variant, solver.ErrUnknownVariant)
+}
@@ -1,22 +1,35 @@
 package d9
 
 import (
-	"bufio"
 	"container/list"
+	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"slices"
 	"strconv"
 	"strings"
 
+	"advent2024/pkg/parse"
 	"advent2024/pkg/solver"
 )
 
 var day = "d9"
 
+// Description of the puzzle
+var metadata = solver.Metadata{
+	Title:       "Disk Fragmenter",
+	Description: "Compacts files on a disk and computes the filesystem checksum.",
+	Parts: []solver.Part{
+		{Part: 1, Label: "Checksum after moving blocks", ExampleAnswer: "1928"},
+		{Part: 2, Label: "Checksum after moving whole files", ExampleAnswer: "2858"},
+	},
+	InputFormat: "Single line of digits, alternating file and free space lengths.",
+	Example:     `2333133121414131402`,
+}
+
 func init() {
 	solver.Register(day, func() solver.PuzzleSolver {
-		return NewSolver()
+		return NewSolverWithCtx()
 	})
 }
 
@@ -28,53 +41,85 @@ type Block struct {
 }
 
 type PuzzleStruct struct {
-	inputStr  *string
 	inputInts *[]int
 
-	blockList list.List
+	defrag defrag
+}
+
+// State of the stepwise defragmentation
+type defrag struct {
+	blockList *list.List
+	cursor    *list.Element
+	step      int
+	done      bool
+}
+
+// Snapshot of the defragmentation after a step
+type Step struct {
+	Step     int    `json:"step"`
+	File     int    `json:"file"`
+	Moved    bool   `json:"moved"`
+	Checksum int    `json:"checksum"`
+	Disk     string `json:"disk,omitempty"`
+	Done     bool   `json:"done"`
 }
 
+// Disks longer than this are not rendered into the Step
+const maxRenderedDisk = 256
+
 func NewSolver() *PuzzleStruct {
 	return &PuzzleStruct{}
 }
 
+// Returns description of the puzzle
+func (p *PuzzleStruct) Metadata() solver.Metadata {
+	return metadata
+}
+
+// Returns summary of the parsed input
+// Length of the disk is the number of blocks including free space
+func (p *PuzzleStruct) Describe() map[string]int64 {
+	length := 0
+	for _, size := range *p.inputInts {
+		length += size
+	}
+
+	return map[string]int64{"length": int64(length), "files": int64(len(*p.inputInts)/2 + 1)}
+}
+
+// Returns how well the input fits, disk map of more than one file,
+// files of the disk map are never empty
+func (p *PuzzleStruct) Score() float64 {
+	ints := *p.inputInts
+
+	if len(ints) < 3 {
+		return 0.3
+	}
+
+	for i := 0; i < len(ints); i += 2 {
+		if ints[i] == 0 {
+			return 0.5
+		}
+	}
+
+	return 0.9
+}
+
 func (p *PuzzleStruct) Init(reader io.Reader) error {
-	inputStr, inputInts, err := parseInput(bufio.NewScanner(reader))
+	inputInts, err := parseInput(reader)
 
 	if err != nil {
-		log.Print(err)
 		return err
 	}
 
-	p.inputStr = inputStr
 	p.inputInts = inputInts
 
 	if err := validateInput(inputInts); err != nil {
-		log.Print(err)
 		return err
 	}
 
-	space := false
-	blockid := 0
-
-	for i := 0; i < len(*p.inputInts); i++ {
-		if !space {
-			p.blockList.PushBack(&Block{size: (*p.inputInts)[i],
-				blockid: blockid,
-				space:   false,
-				moved:   false})
-		} else {
-			p.blockList.PushBack(&Block{size: (*p.inputInts)[i],
-				blockid: 0,
-				space:   true,
-				moved:   false})
-		}
-
-		space = !space
-		if !space {
-			blockid++
-		}
-	}
+	stepList := newBlockList(*p.inputInts)
+	p.defrag = defrag{blockList: stepList, cursor: stepList.Back()}
 
 	return nil
 }
@@ -84,10 +129,13 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 	case 1:
 		sum := 0
 
+		// blocks are consumed from a copy, parsed input is kept intact
+		ints := slices.Clone(*p.inputInts)
+
 		front_array_idx := 0
 		front_block_idx := 0
-		back_array_idx := len(*p.inputInts) - 1
-		back_block_idx := len(*p.inputInts) / 2
+		back_array_idx := len(ints) - 1
+		back_block_idx := len(ints) / 2
 
 		space := false
 
@@ -95,12 +143,12 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 		// go through the disk by index
 		for disk_idx := 0; ; disk_idx++ {
 			// until front is empty
-			for (*p.inputInts)[front_array_idx] == 0 {
+			for ints[front_array_idx] == 0 {
 				// move to next block
 				front_array_idx++
 
 				// if block is out of bounds break
-				if front_array_idx >= len(*p.inputInts) {
+				if front_array_idx >= len(ints) {
 					break outer
 				}
 
@@ -123,19 +171,30 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 				sum += disk_idx * front_block_idx
 
 				// lower the front block count
-				(*p.inputInts)[front_array_idx] = (*p.inputInts)[front_array_idx] - 1
+				ints[front_array_idx] = ints[front_array_idx] - 1
 				// if space
 			} else {
+				// skip files of size 0 at the back
+				for back_array_idx > front_array_idx && ints[back_array_idx] == 0 {
+					back_array_idx -= 2
+					back_block_idx -= 1
+				}
+
+				// no file left behind the space
+				if back_array_idx < front_array_idx {
+					break
+				}
+
 				// increase the sum by the back block
 				sum += disk_idx * back_block_idx
 
 				// lower the back block count
-				(*p.inputInts)[back_array_idx] = (*p.inputInts)[back_array_idx] - 1
+				ints[back_array_idx] = ints[back_array_idx] - 1
 				// lower the front space count
-				(*p.inputInts)[front_array_idx] = (*p.inputInts)[front_array_idx] - 1
+				ints[front_array_idx] = ints[front_array_idx] - 1
 
 				// if we are out of block at the back move to next back block
-				if (*p.inputInts)[back_array_idx] == 0 {
+				if ints[back_array_idx] == 0 {
 					// block < space < block
 					back_array_idx -= 2
 					// block id -1
@@ -148,7 +207,10 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 	case 2:
 		sum := 0
 
-		for back := p.blockList.Back(); back != p.blockList.Front(); back = back.Prev() {
+		// files are moved within a fresh list
+		blockList := newBlockList(*p.inputInts)
+
+		for back := blockList.Back(); back != blockList.Front(); back = back.Prev() {
 
 			block := back.Value.(*Block)
 
@@ -156,10 +218,10 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 				continue
 			}
 
-			back, _ = tryToMove(&p.blockList, back)
+			back, _ = tryToMove(blockList, back)
 		}
 
-		sum = Checksum(&p.blockList)
+		sum = Checksum(blockList)
 
 		return strconv.Itoa(sum), nil
 	}
@@ -167,41 +229,121 @@ func (p *PuzzleStruct) Solve(part int) (string, error) {
 	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
 }
 
-func parseInput(sc *bufio.Scanner) (*string, *[]int, error) {
+// Tries to move one file of the part 2 defragmentation
+// Files are processed from the back of the disk
+// Returns serialised Step, ErrNoMoreSteps after all files were processed
+func (p *PuzzleStruct) Next() (string, error) {
+	d := &p.defrag
 
-	var line string
-	var inputInts []int
+	if d.done {
+		return "", solver.ErrNoMoreSteps
+	}
+
+	// skip spaces and files moved already
+	for d.cursor != d.blockList.Front() && (d.cursor.Value.(*Block).space || d.cursor.Value.(*Block).moved) {
+		d.cursor = d.cursor.Prev()
+	}
+
+	step := Step{File: -1}
+
+	if d.cursor == d.blockList.Front() {
+		d.done = true
+	} else {
+		step.File = d.cursor.Value.(*Block).blockid
+
+		next, err := tryToMove(d.blockList, d.cursor)
+		step.Moved = err == nil
+
+		d.cursor = next.Prev()
+	}
+
+	d.step++
+
+	step.Step = d.step
+	step.Done = d.done
+	step.Checksum = Checksum(d.blockList)
+	step.Disk = Render(d.blockList, maxRenderedDisk)
+
+	b, err := json.Marshal(step)
+
+	if err != nil {
+		return "", fmt.Errorf("%s unable to marshal step: %w", day, err)
+	}
+
+	return string(b), nil
+}
 
-	// expecting only 1 line
-	for sc.Scan() {
-		line = strings.TrimSpace(sc.Text())
+// Creates list of blocks from the disk map
+func newBlockList(ints []int) *list.List {
+	l := list.New()
 
-		inputInts = make([]int, len(line))
+	space := false
+	blockid := 0
 
-		for i, c := range line {
-			n, err := strconv.Atoi(string(c))
-			if err != nil {
-				log.Print(err)
-				return nil, nil, fmt.Errorf("%s unable to convert %s to int: %w", day, string(c), solver.ErrInvalidInput)
+	for i := 0; i < len(ints); i++ {
+		if !space {
+			l.PushBack(&Block{size: ints[i],
+				blockid: blockid,
+				space:   false,
+				moved:   false})
+		} else {
+			l.PushBack(&Block{size: ints[i],
+				blockid: 0,
+				space:   true,
+				moved:   false})
+		}
+
+		space = !space
+		if !space {
+			blockid++
+		}
+	}
+
+	return l
+}
+
+// Renders the disk, files as their id, free space as '.'
+// Returns empty string if the disk is longer than limit
+func Render(l *list.List, limit int) string {
+	var sb strings.Builder
+
+	for c := l.Front(); c != nil; c = c.Next() {
+		block := c.Value.(*Block)
+
+		if sb.Len()+block.size > limit {
+			return ""
+		}
+
+		for i := 0; i < block.size; i++ {
+			if block.space {
+				sb.WriteByte('.')
+			} else {
+				sb.WriteString(strconv.Itoa(block.blockid % 10))
 			}
-			inputInts[i] = n
 		}
 	}
 
-	if sc.Err() != nil {
-		return nil, nil, fmt.Errorf("%s scan error %s: %w", day, sc.Err(), solver.ErrInvalidInput)
+	return sb.String()
+}
+
+func parseInput(reader io.Reader) (*[]int, error) {
+
+	inputInts, err := parse.Digits(reader)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s %w", day, err)
 	}
 
-	return &line, &inputInts, nil
+	return &inputInts, nil
 }
 
 func validateInput(ints *[]int) error {
 	if ints == nil {
-		return fmt.Errorf("%s empty rules: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "disk map", "", "empty disk map"))
 	} else if len(*ints) == 0 {
-		return fmt.Errorf("%s empty rules: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "disk map", "", "empty disk map"))
 	} else if len(*ints)%2 != 1 {
-		return fmt.Errorf("%s non odd input length: %w", day, solver.ErrInvalidInput)
+		return fmt.Errorf("%s %w", day, parse.Errorf(0, 0, "odd number of digits", "", "even input length %d", len(*ints)))
 	}
 
 	return nil
@@ -251,7 +393,7 @@ func Checksum(l *list.List) int {
 	sum := 0
 	idx := 0
 
-	for c := l.Front(); c != l.Back(); c = c.Next() {
+	for c := l.Front(); c != nil; c = c.Next() {
 		if c.Value.(*Block).space {
 			idx += c.Value.(*Block).size
 		} else {
@@ -3,6 +3,7 @@ package d9
 import (
 	"advent2024/pkg/solver"
 	"context"
+	"encoding/json"
 	"errors"
 	"strings"
 	"testing"
@@ -22,6 +23,8 @@ func TestPart1(t *testing.T) {
 		{name: "12345", input: "12345", want: "60"},
 		{name: "20202", input: "20202", want: "23"},
 		{name: "singleblock", input: "1", want: "0"},
+		{name: "trailing empty files", input: "19000", want: "0"},
+		{name: "empty file behind space", input: "13100", want: "1"},
 	}
 
 	for _, c := range cases {
@@ -42,6 +45,10 @@ func TestPart2(t *testing.T) {
 		name, input, want string
 	}{
 		{name: "test input", input: inputTest, want: "2858"},
+		{name: "12345", input: "12345", want: "132"},
+		{name: "20202", input: "20202", want: "23"},
+		{name: "trailing empty files", input: "19000", want: "0"},
+		{name: "empty file behind space", input: "13100", want: "1"},
 	}
 
 	for _, c := range cases {
@@ -148,6 +155,60 @@ func TestCtxTimeout(t *testing.T) {
 	}
 }
 
+func TestNext(t *testing.T) {
+	puzzle := NewSolverWithCtx()
+	_ = puzzle.InitCtx(context.Background(), strings.NewReader(inputTest))
+
+	var last Step
+	var err error
+	var got string
+
+	stepped := make(map[int]bool)
+
+	for got, err = puzzle.Next(context.Background()); err == nil; got, err = puzzle.Next(context.Background()) {
+		if err := json.Unmarshal([]byte(got), &last); err != nil {
+			t.Fatalf("unable to unmarshal step %s: %v", got, err)
+		}
+
+		if stepped[last.File] {
+			t.Errorf("file %d stepped again in step %d", last.File, last.Step)
+		}
+
+		stepped[last.File] = true
+	}
+
+	// files 9 to 1 and the final step
+	if last.Step != 10 {
+		t.Errorf("Got %d steps expected 10", last.Step)
+	}
+
+	if !errors.Is(err, solver.ErrNoMoreSteps) {
+		t.Errorf("Got %v expected %v", err, solver.ErrNoMoreSteps)
+	}
+
+	want := "00992111777.44.333....5555.6666.....8888.."
+
+	if !last.Done || last.Checksum != 2858 || last.Disk != want {
+		t.Errorf("Got %+v expected checksum 2858 and disk %s", last, want)
+	}
+}
+
+func TestNextChecksum(t *testing.T) {
+	puzzle := NewSolverWithCtx()
+	_ = puzzle.InitCtx(context.Background(), strings.NewReader("12345"))
+
+	got, _ := puzzle.Next(context.Background())
+
+	var step Step
+	if err := json.Unmarshal([]byte(got), &step); err != nil {
+		t.Fatalf("unable to unmarshal step %s: %v", got, err)
+	}
+
+	if step.Checksum != 132 {
+		t.Errorf("Got %d expected checksum 132", step.Checksum)
+	}
+}
+
 func BenchmarkParts(b *testing.B) {
 	input := input
 
@@ -195,3 +256,28 @@ func BenchmarkPartsCtx(b *testing.B) {
 		})
 	}
 }
+
+func TestScore(t *testing.T) {
+	cases := []struct {
+		name, input string
+		want        float64
+	}{
+		{"test input", inputTest, 0.9},
+		{"single file", "1", 0.3},
+		{"empty files", "19000", 0.5},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			puzzle := NewSolver()
+
+			if err := puzzle.Init(strings.NewReader(c.input)); err != nil {
+				t.Fatalf("Got %v expected nil", err)
+			}
+
+			if got := puzzle.Score(); got != c.want {
+				t.Errorf("Got %v expected %v", got, c.want)
+			}
+		})
+	}
+}
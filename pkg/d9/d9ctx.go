@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 )
 
@@ -12,18 +13,17 @@ type PuzzleStructWithCtx struct {
 	PuzzleStruct
 }
 
-func init() {
-	solver.RegisterWithCtx(day, func() solver.PuzzleSolverWithCtx {
-		return NewSolverWithCtx()
-	})
-}
-
 func NewSolverWithCtx() *PuzzleStructWithCtx {
 	return &PuzzleStructWithCtx{}
 }
 
 func (p *PuzzleStructWithCtx) InitCtx(ctx context.Context, reader io.Reader) error {
-	return p.PuzzleStruct.Init(reader)
+	return solver.InitWithCtx(ctx, reader, p.PuzzleStruct.Init)
+}
+
+// Reports progress of SolveCtx, part 2 one unit per file
+func (p *PuzzleStructWithCtx) ReportsProgress() bool {
+	return true
 }
 
 func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, error) {
@@ -31,10 +31,13 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 	case 1:
 		sum := 0
 
+		// blocks are consumed from a copy, parsed input is kept intact
+		ints := slices.Clone(*p.inputInts)
+
 		front_array_idx := 0
 		front_block_idx := 0
-		back_array_idx := len(*p.inputInts) - 1
-		back_block_idx := len(*p.inputInts) / 2
+		back_array_idx := len(ints) - 1
+		back_block_idx := len(ints) / 2
 
 		space := false
 
@@ -48,12 +51,12 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 			default:
 			}
 			// until front is empty
-			for (*p.inputInts)[front_array_idx] == 0 {
+			for ints[front_array_idx] == 0 {
 				// move to next block
 				front_array_idx++
 
 				// if block is out of bounds break
-				if front_array_idx >= len(*p.inputInts) {
+				if front_array_idx >= len(ints) {
 					break outer
 				}
 
@@ -76,19 +79,19 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 				sum += disk_idx * front_block_idx
 
 				// lower the front block count
-				(*p.inputInts)[front_array_idx] = (*p.inputInts)[front_array_idx] - 1
+				ints[front_array_idx] = ints[front_array_idx] - 1
 				// if space
 			} else {
 				// increase the sum by the back block
 				sum += disk_idx * back_block_idx
 
 				// lower the back block count
-				(*p.inputInts)[back_array_idx] = (*p.inputInts)[back_array_idx] - 1
+				ints[back_array_idx] = ints[back_array_idx] - 1
 				// lower the front space count
-				(*p.inputInts)[front_array_idx] = (*p.inputInts)[front_array_idx] - 1
+				ints[front_array_idx] = ints[front_array_idx] - 1
 
 				// if we are out of block at the back move to next back block
-				if (*p.inputInts)[back_array_idx] == 0 {
+				if ints[back_array_idx] == 0 {
 					// block < space < block
 					back_array_idx -= 2
 					// block id -1
@@ -101,7 +104,13 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 	case 2:
 		sum := 0
 
-		for back := p.blockList.Back(); back != p.blockList.Front(); back = back.Prev() {
+		// files are moved within a fresh list
+		blockList := newBlockList(*p.inputInts)
+
+		files := len(*p.inputInts)/2 + 1
+		done := 0
+
+		for back := blockList.Back(); back != blockList.Front(); back = back.Prev() {
 
 			select {
 			case <-ctx.Done():
@@ -115,13 +124,29 @@ func (p *PuzzleStructWithCtx) SolveCtx(ctx context.Context, part int) (string, e
 				continue
 			}
 
-			back, _ = tryToMove(&p.blockList, back)
+			back, _ = tryToMove(blockList, back)
+
+			done++
+			solver.ReportProgress(ctx, done, files)
 		}
 
-		sum = Checksum(&p.blockList)
+		solver.ReportProgress(ctx, files, files)
+
+		sum = Checksum(blockList)
 
 		return strconv.Itoa(sum), nil
 	}
 
 	return "", fmt.Errorf("%s unknown part %d: %w", day, part, solver.ErrUnknownPart)
 }
+
+// Tries to move one file of the part 2 defragmentation
+func (p *PuzzleStructWithCtx) Next(ctx context.Context) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", solver.ErrTimeout
+	default:
+	}
+
+	return p.PuzzleStruct.Next()
+}
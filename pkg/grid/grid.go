@@ -0,0 +1,141 @@
+// Package provides rectangular grids shared by the grid puzzles
+package grid
+
+import (
+	"slices"
+	"strings"
+)
+
+// Rectangular grid of cells
+// Cells are stored row by row in one slice
+type Grid[T comparable] struct {
+	width, height int
+	cells         []T
+}
+
+// Constructor, all cells have zero value
+func New[T comparable](width, height int) *Grid[T] {
+	return &Grid[T]{width: width, height: height, cells: make([]T, width*height)}
+}
+
+// Returns width of the grid
+func (g *Grid[T]) Width() int {
+	return g.width
+}
+
+// Returns height of the grid
+func (g *Grid[T]) Height() int {
+	return g.height
+}
+
+// Returns true if the point lies in the grid
+func (g *Grid[T]) In(p Point) bool {
+	return p.X >= 0 && p.X < g.width && p.Y >= 0 && p.Y < g.height
+}
+
+// Returns value of the cell, false if the point lies outside of the grid
+func (g *Grid[T]) Get(p Point) (T, bool) {
+	if !g.In(p) {
+		var zero T
+		return zero, false
+	}
+
+	return g.cells[p.Y*g.width+p.X], true
+}
+
+// Returns value of the cell, panics outside of the grid
+func (g *Grid[T]) At(p Point) T {
+	if !g.In(p) {
+		panic("grid: point outside of grid")
+	}
+
+	return g.cells[p.Y*g.width+p.X]
+}
+
+// Sets value of the cell, panics outside of the grid
+func (g *Grid[T]) Set(p Point, v T) {
+	if !g.In(p) {
+		panic("grid: point outside of grid")
+	}
+
+	g.cells[p.Y*g.width+p.X] = v
+}
+
+// Returns true if the cell lies in the grid and holds the value
+func (g *Grid[T]) Is(p Point, v T) bool {
+	c, ok := g.Get(p)
+	return ok && c == v
+}
+
+// Returns the orthogonal neighbours lying in the grid
+func (g *Grid[T]) Neighbours4(p Point) []Point {
+	return g.inside(p.Neighbours4())
+}
+
+// Returns the neighbours including diagonals lying in the grid
+func (g *Grid[T]) Neighbours8(p Point) []Point {
+	return g.inside(p.Neighbours8())
+}
+
+// Returns all points of the grid in reading order
+func (g *Grid[T]) Points() []Point {
+	result := make([]Point, 0, len(g.cells))
+
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			result = append(result, Point{x, y})
+		}
+	}
+
+	return result
+}
+
+// Returns points of the cells holding the value in reading order
+func (g *Grid[T]) Find(v T) []Point {
+	var result []Point
+
+	for i, c := range g.cells {
+		if c == v {
+			result = append(result, Point{i % g.width, i / g.width})
+		}
+	}
+
+	return result
+}
+
+// Returns deep copy of the grid
+func (g *Grid[T]) Clone() *Grid[T] {
+	return &Grid[T]{width: g.width, height: g.height, cells: slices.Clone(g.cells)}
+}
+
+// Renders the grid as text, one line per row
+func (g *Grid[T]) Render(cell func(T) byte) string {
+	var sb strings.Builder
+
+	sb.Grow((g.width + 1) * g.height)
+
+	for y := 0; y < g.height; y++ {
+		if y > 0 {
+			sb.WriteByte('\n')
+		}
+
+		for _, c := range g.cells[y*g.width : (y+1)*g.width] {
+			sb.WriteByte(cell(c))
+		}
+	}
+
+	return sb.String()
+}
+
+// Filters points lying in the grid
+func (g *Grid[T]) inside(points []Point) []Point {
+	result := points[:0]
+
+	for _, p := range points {
+		if g.In(p) {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,161 @@
+package grid
+
+import (
+	"advent2024/pkg/parse"
+	"advent2024/pkg/solver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var inputTest = `..#
+.^.
+#..`
+
+func TestParse(t *testing.T) {
+	g, err := ParseBytes(strings.NewReader(inputTest+"\n\n"), ".#^")
+
+	if err != nil {
+		t.Fatalf("got %v expected nil", err)
+	}
+
+	if g.Width() != 3 || g.Height() != 3 {
+		t.Errorf("got %dx%d expected 3x3", g.Width(), g.Height())
+	}
+
+	if got := g.Render(Byte); got != inputTest {
+		t.Errorf("got %q expected %q", got, inputTest)
+	}
+
+	if got, want := g.Find('#'), []Point{{2, 0}, {0, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v expected %v", got, want)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	cases := []struct {
+		name, input string
+		line, col   int
+	}{
+		{"empty input", "", 0, 0},
+		{"unknown character", "...\n.x.", 2, 2},
+		{"unequal row lengths", "...\n\n....", 3, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ParseBytes(strings.NewReader(c.input), ".#")
+
+			var ie *parse.InputError
+
+			if !errors.As(err, &ie) || ie.Line != c.line || ie.Column != c.col {
+				t.Fatalf("got %v expected error at %d:%d", err, c.line, c.col)
+			}
+
+			if !errors.Is(err, solver.ErrInvalidInput) {
+				t.Errorf("got %v expected %v", err, solver.ErrInvalidInput)
+			}
+		})
+	}
+}
+
+func TestParseInts(t *testing.T) {
+	g, err := Parse(strings.NewReader("01\n.9"), func(b byte) (int, error) {
+		switch {
+		case b == '.':
+			return -1, nil
+		case b >= '0' && b <= '9':
+			return int(b - '0'), nil
+		}
+
+		return 0, fmt.Errorf("not a digit")
+	})
+
+	if err != nil {
+		t.Fatalf("got %v expected nil", err)
+	}
+
+	if got := g.At(Point{1, 1}); got != 9 {
+		t.Errorf("got %d expected 9", got)
+	}
+
+	if got := g.At(Point{0, 1}); got != -1 {
+		t.Errorf("got %d expected -1", got)
+	}
+}
+
+func TestAccess(t *testing.T) {
+	g := New[byte](3, 2)
+	g.Set(Point{2, 1}, 'x')
+
+	if !g.Is(Point{2, 1}, 'x') || g.Is(Point{3, 1}, 0) {
+		t.Errorf("unexpected cell values")
+	}
+
+	if _, ok := g.Get(Point{-1, 0}); ok {
+		t.Errorf("got ok outside of grid")
+	}
+
+	c := g.Clone()
+	c.Set(Point{0, 0}, 'y')
+
+	if g.Is(Point{0, 0}, 'y') {
+		t.Errorf("clone shares cells")
+	}
+
+	if got := len(g.Points()); got != 6 {
+		t.Errorf("got %d points expected 6", got)
+	}
+}
+
+func TestNeighbours(t *testing.T) {
+	g := New[int](3, 3)
+
+	cases := []struct {
+		name string
+		got  []Point
+		want []Point
+	}{
+		{"4-way corner", g.Neighbours4(Point{0, 0}), []Point{{1, 0}, {0, 1}}},
+		{"8-way corner", g.Neighbours8(Point{0, 0}), []Point{{1, 0}, {0, 1}, {1, 1}}},
+		{"4-way center", g.Neighbours4(Point{1, 1}), []Point{{1, 0}, {2, 1}, {1, 2}, {0, 1}}},
+		{"8-way unbounded", Point{0, 0}.Neighbours8()[:3], []Point{{-1, -1}, {0, -1}, {1, -1}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if !reflect.DeepEqual(c.got, c.want) {
+				t.Errorf("got %v expected %v", c.got, c.want)
+			}
+		})
+	}
+}
+
+func TestDirection(t *testing.T) {
+	p := Point{1, 1}
+
+	if got := p.Move(Up); got != (Point{1, 0}) {
+		t.Errorf("got %v expected {1 0}", got)
+	}
+
+	cases := []struct {
+		got, want Direction
+	}{
+		{Up.TurnRight(), Right},
+		{Left.TurnRight(), Up},
+		{Up.TurnLeft(), Left},
+		{Down.Reverse(), Up},
+	}
+
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("got %v expected %v", c.got, c.want)
+		}
+	}
+
+	if got := p.Add(Point{2, 3}).Sub(Point{1, 1}).Mul(2).Neg(); got != (Point{-4, -6}) {
+		t.Errorf("got %v expected {-4 -6}", got)
+	}
+}
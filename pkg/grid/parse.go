@@ -0,0 +1,58 @@
+// Package provides parsing of the grid input
+package grid
+
+import (
+	"io"
+
+	"advent2024/pkg/parse"
+)
+
+// Parses rectangular grid, one row per line
+// Lines are trimmed, empty lines are skipped, cell converts one byte
+// of the line and returns error for invalid bytes
+// Returns parse.InputError wrapping solver.ErrInvalidInput
+func Parse[T comparable](reader io.Reader, cell func(b byte) (T, error)) (*Grid[T], error) {
+	rows, err := parse.Grid(reader, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	g := New[T](len(rows[0].Text), len(rows))
+
+	for y, row := range rows {
+		for x := 0; x < len(row.Text); x++ {
+			v, err := cell(row.Text[x])
+
+			if err != nil {
+				return nil, parse.Errorf(row.Num, x+1, "", row.Text[x:x+1], "%v", err)
+			}
+
+			g.cells[y*g.width+x] = v
+		}
+	}
+
+	return g, nil
+}
+
+// Parses grid of bytes, allowed lists the valid bytes, empty allows any
+func ParseBytes(reader io.Reader, allowed string) (*Grid[byte], error) {
+	rows, err := parse.Grid(reader, allowed)
+
+	if err != nil {
+		return nil, err
+	}
+
+	g := New[byte](len(rows[0].Text), len(rows))
+
+	for y, row := range rows {
+		copy(g.cells[y*g.width:], row.Text)
+	}
+
+	return g, nil
+}
+
+// Renders byte cells as they are, for Render of byte grids
+func Byte(b byte) byte {
+	return b
+}
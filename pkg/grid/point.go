@@ -0,0 +1,104 @@
+// Package provides points and directions of the grid
+package grid
+
+// Position in the grid, X grows to the right, Y grows down
+type Point struct {
+	X, Y int
+}
+
+// Direction in the grid, ordered clockwise
+type Direction int
+
+const (
+	Up Direction = iota
+	Right
+	Down
+	Left
+)
+
+// Directions ordered clockwise starting up
+var Directions = [4]Direction{Up, Right, Down, Left}
+
+// Offsets of the 4 orthogonal neighbours, ordered as Directions
+var Offsets4 = [4]Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+
+// Offsets of the 8 neighbours including diagonals, in reading order
+var Offsets8 = [8]Point{
+	{-1, -1}, {0, -1}, {1, -1},
+	{-1, 0}, {1, 0},
+	{-1, 1}, {0, 1}, {1, 1},
+}
+
+// Returns sum of the points
+func (p Point) Add(o Point) Point {
+	return Point{p.X + o.X, p.Y + o.Y}
+}
+
+// Returns difference of the points
+func (p Point) Sub(o Point) Point {
+	return Point{p.X - o.X, p.Y - o.Y}
+}
+
+// Returns point multiplied by k
+func (p Point) Mul(k int) Point {
+	return Point{p.X * k, p.Y * k}
+}
+
+// Returns point with both coordinates negated
+func (p Point) Neg() Point {
+	return Point{-p.X, -p.Y}
+}
+
+// Returns the neighbouring point in the direction
+func (p Point) Move(d Direction) Point {
+	return p.Add(d.Offset())
+}
+
+// Returns the 4 orthogonal neighbours, ordered as Directions
+// Neighbours may lie outside of any grid
+func (p Point) Neighbours4() []Point {
+	result := make([]Point, 0, len(Offsets4))
+
+	for _, o := range Offsets4 {
+		result = append(result, p.Add(o))
+	}
+
+	return result
+}
+
+// Returns the 8 neighbours including diagonals, in reading order
+// Neighbours may lie outside of any grid
+func (p Point) Neighbours8() []Point {
+	result := make([]Point, 0, len(Offsets8))
+
+	for _, o := range Offsets8 {
+		result = append(result, p.Add(o))
+	}
+
+	return result
+}
+
+// Returns offset of one step in the direction
+func (d Direction) Offset() Point {
+	return Offsets4[d&3]
+}
+
+// Returns direction after turning 90 degrees clockwise
+func (d Direction) TurnRight() Direction {
+	return (d + 1) & 3
+}
+
+// Returns direction after turning 90 degrees counterclockwise
+func (d Direction) TurnLeft() Direction {
+	return (d + 3) & 3
+}
+
+// Returns the opposite direction
+func (d Direction) Reverse() Direction {
+	return (d + 2) & 3
+}
+
+// Returns name of the direction
+func (d Direction) String() string {
+	return [4]string{"up", "right", "down", "left"}[d&3]
+}
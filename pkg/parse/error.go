@@ -0,0 +1,17 @@
+// Package provides positioned errors of the puzzle input
+package parse
+
+import (
+	"fmt"
+
+	"advent2024/pkg/solver"
+)
+
+// Error of the puzzle input, see solver.InputError
+// Defined by the solver package to travel over the external solver protocol
+type InputError = solver.InputError
+
+// Constructor
+func Errorf(line, column int, expected, found string, format string, a ...any) *InputError {
+	return &InputError{Line: line, Column: column, Expected: expected, Found: found, Reason: fmt.Sprintf(format, a...)}
+}
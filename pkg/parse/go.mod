@@ -0,0 +1,3 @@
+module advent2024/pkg/parse
+
+go 1.22.2
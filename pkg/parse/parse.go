@@ -0,0 +1,363 @@
+// Package provides parsing helpers shared by the puzzles
+// All helpers return *InputError wrapping solver.ErrInvalidInput
+package parse
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Maximum length of the input line
+const maxLine = 16 * 1024 * 1024
+
+// Line of the input, Num is 1-based
+// Text has trailing whitespace removed
+type Line struct {
+	Num  int
+	Text string
+}
+
+// Part of the line, Column is 1-based
+type Field struct {
+	Line   int
+	Column int
+	Text   string
+}
+
+// Line of key: values, e.g. 190: 10 19
+type KeyValue struct {
+	Line   int
+	Key    int
+	Values []int
+}
+
+// Returns all lines of the input including empty ones
+func Lines(reader io.Reader) ([]Line, error) {
+	sc := bufio.NewScanner(reader)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLine)
+
+	var lines []Line
+
+	for n := 1; sc.Scan(); n++ {
+		lines = append(lines, Line{Num: n, Text: strings.TrimRightFunc(sc.Text(), unicode.IsSpace)})
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, Errorf(len(lines)+1, 0, "", "", "unable to read input: %v", err)
+	}
+
+	return lines, nil
+}
+
+// Returns non-empty lines of the input, error if there are none
+func NonEmpty(reader io.Reader) ([]Line, error) {
+	lines, err := Lines(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := lines[:0]
+
+	for _, l := range lines {
+		if strings.TrimSpace(l.Text) != "" {
+			result = append(result, l)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, Errorf(0, 0, "non-empty input", "", "empty input")
+	}
+
+	return result, nil
+}
+
+// Returns the only non-empty line of the input
+func SingleLine(reader io.Reader) (Line, error) {
+	lines, err := NonEmpty(reader)
+
+	if err != nil {
+		return Line{}, err
+	}
+
+	if len(lines) > 1 {
+		return Line{}, Errorf(lines[1].Num, 0, "single line", lines[1].Text, "unexpected second line")
+	}
+
+	return lines[0], nil
+}
+
+// Returns whitespace separated integers of every non-empty line
+// n > 0 requires exactly n integers on every line
+func Ints(reader io.Reader, n int) ([][]int, error) {
+	lines, err := NonEmpty(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]int, 0, len(lines))
+
+	for _, l := range lines {
+		ints, err := l.Ints()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if n > 0 && len(ints) != n {
+			return nil, Errorf(l.Num, 0, fmt.Sprintf("%d numbers", n), l.Text, "expected %d numbers, found %d", n, len(ints))
+		}
+
+		result = append(result, ints)
+	}
+
+	return result, nil
+}
+
+// Splits the input at empty lines into n sections of non-empty lines
+// Consecutive empty lines separate sections once
+func Sections(reader io.Reader, n int) ([][]Line, error) {
+	lines, err := Lines(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var sections [][]Line
+	var current []Line
+
+	for _, l := range lines {
+		if strings.TrimSpace(l.Text) != "" {
+			current = append(current, l)
+			continue
+		}
+
+		if len(current) > 0 {
+			sections = append(sections, current)
+			current = nil
+		}
+	}
+
+	if len(current) > 0 {
+		sections = append(sections, current)
+	}
+
+	if len(sections) > n {
+		return nil, Errorf(sections[n][0].Num, 0, fmt.Sprintf("%d sections", n), sections[n][0].Text, "unexpected section %d", n+1)
+	}
+
+	if len(sections) < n {
+		return nil, Errorf(0, 0, fmt.Sprintf("%d sections", n), "", "expected %d sections, found %d", n, len(sections))
+	}
+
+	return sections, nil
+}
+
+// Returns digits of the only non-empty line, e.g. 2333133121414131402
+func Digits(reader io.Reader) ([]int, error) {
+	l, err := SingleLine(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(l.Text)
+	offset := strings.Index(l.Text, text)
+
+	result := make([]int, len(text))
+
+	for i := 0; i < len(text); i++ {
+		if text[i] < '0' || text[i] > '9' {
+			return nil, Errorf(l.Num, offset+i+1, "digit", text[i:i+1], "not a digit")
+		}
+
+		result[i] = int(text[i] - '0')
+	}
+
+	return result, nil
+}
+
+// Returns non-empty lines of key: values, e.g. 190: 10 19
+func KeyValues(reader io.Reader) ([]KeyValue, error) {
+	lines, err := NonEmpty(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]KeyValue, 0, len(lines))
+
+	for _, l := range lines {
+		parts := l.Split(":")
+
+		if len(parts) != 2 {
+			return nil, Errorf(l.Num, 0, "key: values", l.Text, "expected key: values")
+		}
+
+		key, err := parts[0].Trim().Int()
+
+		if err != nil {
+			return nil, err
+		}
+
+		values, err := parts[1].Ints()
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, KeyValue{Line: l.Num, Key: key, Values: values})
+	}
+
+	return result, nil
+}
+
+// Returns rows of rectangular character grid
+// Empty lines are skipped, allowed lists valid characters, empty allows any
+func Grid(reader io.Reader, allowed string) ([]Line, error) {
+	lines, err := NonEmpty(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	width := len(strings.TrimSpace(lines[0].Text))
+
+	for i, l := range lines {
+		text := strings.TrimSpace(l.Text)
+		offset := strings.Index(l.Text, text)
+
+		if len(text) != width {
+			return nil, Errorf(l.Num, 0, fmt.Sprintf("row of length %d", width), l.Text, "row length %d differs from %d", len(text), width)
+		}
+
+		if allowed != "" {
+			for x := 0; x < len(text); x++ {
+				if strings.IndexByte(allowed, text[x]) < 0 {
+					return nil, Errorf(l.Num, offset+x+1, "one of "+allowed, text[x:x+1], "unknown character")
+				}
+			}
+		}
+
+		lines[i] = Line{Num: l.Num, Text: text}
+	}
+
+	return lines, nil
+}
+
+// Returns whitespace separated fields of the line
+func (l Line) Fields() []Field {
+	var result []Field
+
+	start := -1
+
+	for i := 0; i <= len(l.Text); i++ {
+		space := i == len(l.Text) || unicode.IsSpace(rune(l.Text[i]))
+
+		switch {
+		case !space && start < 0:
+			start = i
+		case space && start >= 0:
+			result = append(result, Field{Line: l.Num, Column: start + 1, Text: l.Text[start:i]})
+			start = -1
+		}
+	}
+
+	return result
+}
+
+// Returns parts of the line separated by sep
+func (l Line) Split(sep string) []Field {
+	parts := strings.Split(l.Text, sep)
+	result := make([]Field, 0, len(parts))
+
+	column := 1
+
+	for _, part := range parts {
+		result = append(result, Field{Line: l.Num, Column: column, Text: part})
+		column += len(part) + len(sep)
+	}
+
+	return result
+}
+
+// Returns whitespace separated integers of the line
+func (l Line) Ints() ([]int, error) {
+	return Field{Line: l.Num, Column: 1, Text: l.Text}.Ints()
+}
+
+// Returns integers of the line separated by sep, e.g. 75,47,61
+func (l Line) SplitInts(sep string) ([]int, error) {
+	return Field{Line: l.Num, Column: 1, Text: l.Text}.SplitInts(sep)
+}
+
+// Returns field without surrounding whitespace
+func (f Field) Trim() Field {
+	text := strings.TrimSpace(f.Text)
+
+	if text == "" {
+		return Field{Line: f.Line, Column: f.Column}
+	}
+
+	return Field{Line: f.Line, Column: f.Column + strings.Index(f.Text, text), Text: text}
+}
+
+// Returns integer value of the field
+func (f Field) Int() (int, error) {
+	v, err := strconv.Atoi(f.Text)
+
+	if err != nil {
+		if f.Text == "" {
+			return 0, Errorf(f.Line, f.Column, "number", "", "missing number")
+		}
+
+		return 0, Errorf(f.Line, f.Column, "number", f.Text, "not a number")
+	}
+
+	return v, nil
+}
+
+// Returns whitespace separated integers of the field
+func (f Field) Ints() ([]int, error) {
+	fields := Line{Num: f.Line, Text: f.Text}.Fields()
+	result := make([]int, 0, len(fields))
+
+	for _, field := range fields {
+		field.Column += f.Column - 1
+
+		v, err := field.Int()
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, v)
+	}
+
+	return result, nil
+}
+
+// Returns integers of the field separated by sep
+func (f Field) SplitInts(sep string) ([]int, error) {
+	parts := Line{Num: f.Line, Text: f.Text}.Split(sep)
+	result := make([]int, 0, len(parts))
+
+	for _, part := range parts {
+		part.Column += f.Column - 1
+
+		v, err := part.Trim().Int()
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, v)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,120 @@
+package parse
+
+import (
+	"advent2024/pkg/solver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	t.Run("ints", func(t *testing.T) {
+		got, err := Ints(strings.NewReader("3   4\n\n4 3\n"), 2)
+		want := [][]int{{3, 4}, {4, 3}}
+
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, %v expected %v", got, err, want)
+		}
+	})
+
+	t.Run("sections", func(t *testing.T) {
+		got, err := Sections(strings.NewReader("47|53\n97|13\n\n\n75,47\n"), 2)
+
+		if err != nil || len(got) != 2 || len(got[0]) != 2 || got[1][0].Num != 5 {
+			t.Fatalf("got %v, %v", got, err)
+		}
+
+		ints, err := got[1][0].SplitInts(",")
+
+		if err != nil || !reflect.DeepEqual(ints, []int{75, 47}) {
+			t.Errorf("got %v, %v expected [75 47]", ints, err)
+		}
+	})
+
+	t.Run("digits", func(t *testing.T) {
+		got, err := Digits(strings.NewReader("\n12345\n"))
+
+		if err != nil || !reflect.DeepEqual(got, []int{1, 2, 3, 4, 5}) {
+			t.Errorf("got %v, %v expected [1 2 3 4 5]", got, err)
+		}
+	})
+
+	t.Run("key values", func(t *testing.T) {
+		got, err := KeyValues(strings.NewReader("190: 10 19\n3267: 81 40 27"))
+		want := []KeyValue{{1, 190, []int{10, 19}}, {2, 3267, []int{81, 40, 27}}}
+
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, %v expected %v", got, err, want)
+		}
+	})
+
+	t.Run("grid", func(t *testing.T) {
+		got, err := Grid(strings.NewReader("..#\n.^.\n"), ".#^")
+
+		if err != nil || len(got) != 2 || got[1].Text != ".^." {
+			t.Errorf("got %v, %v", got, err)
+		}
+	})
+}
+
+func TestInputError(t *testing.T) {
+	cases := []struct {
+		name         string
+		parse        func() error
+		line, column int
+		text         string
+	}{
+		{"empty input", func() error { _, err := Ints(strings.NewReader("\n\n"), 0); return err }, 0, 0, ""},
+		{"not a number", func() error { _, err := Ints(strings.NewReader("1 2\n3  x4"), 0); return err }, 2, 4, "x4"},
+		{"wrong count", func() error { _, err := Ints(strings.NewReader("1 2\n3"), 2); return err }, 2, 0, "3"},
+		{"missing section", func() error { _, err := Sections(strings.NewReader("1|2"), 2); return err }, 0, 0, ""},
+		{"extra section", func() error { _, err := Sections(strings.NewReader("1\n\n2\n\n3"), 2); return err }, 5, 0, "3"},
+		{"not a digit", func() error { _, err := Digits(strings.NewReader(" 12a")); return err }, 1, 4, "a"},
+		{"second line", func() error { _, err := Digits(strings.NewReader("12\n34")); return err }, 2, 0, "34"},
+		{"missing separator", func() error { _, err := KeyValues(strings.NewReader("190 10 19")); return err }, 1, 0, "190 10 19"},
+		{"invalid key", func() error { _, err := KeyValues(strings.NewReader(" x: 10 19")); return err }, 1, 2, "x"},
+		{"invalid value", func() error { _, err := KeyValues(strings.NewReader("190: 10 y")); return err }, 1, 9, "y"},
+		{"missing number", func() error { _, err := Line{1, "75,,47"}.SplitInts(","); return err }, 1, 4, ""},
+		{"unequal rows", func() error { _, err := Grid(strings.NewReader("..\n\n..."), ""); return err }, 3, 0, "..."},
+		{"unknown character", func() error { _, err := Grid(strings.NewReader("..\n.x"), "."); return err }, 2, 2, "x"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.parse()
+
+			var ie *InputError
+
+			if !errors.As(err, &ie) {
+				t.Fatalf("got %v expected InputError", err)
+			}
+
+			if ie.Line != c.line || ie.Column != c.column || ie.Found != c.text {
+				t.Errorf("got %d:%d %q expected %d:%d %q", ie.Line, ie.Column, ie.Found, c.line, c.column, c.text)
+			}
+
+			if !errors.Is(err, solver.ErrInvalidInput) {
+				t.Errorf("got %v expected %v", err, solver.ErrInvalidInput)
+			}
+		})
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	cases := []struct {
+		err  *InputError
+		want string
+	}{
+		{Errorf(3, 5, "number", "x1", "not a number"), `line 3, column 5: not a number "x1": invalid input`},
+		{Errorf(3, 0, "2 numbers", "", "expected %d numbers", 2), `line 3: expected 2 numbers: invalid input`},
+		{Errorf(0, 0, "", "", "empty input"), `empty input: invalid input`},
+		{Errorf(1, 0, "", strings.Repeat("a", 100), "too long"), `line 1: too long "` + strings.Repeat("a", 80) + `...": invalid input`},
+	}
+
+	for _, c := range cases {
+		if got := c.err.Error(); got != c.want {
+			t.Errorf("got %s expected %s", got, c.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Package provides detection of the day from the input
+package solver
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Score of valid input of solvers which do not score the input
+const defaultScore = 0.1
+
+// Interface of Puzzle Solver scoring how well the parsed input fits
+// the puzzle, Score is called after successful Init and returns value
+// between 0 (input parsed but not of the puzzle) and 1 (input of the puzzle)
+type Scorer interface {
+	PuzzleSolver
+	Score() float64
+}
+
+// Day which might solve the input
+type Candidate struct {
+	Name  string
+	Score float64
+}
+
+// Initializes every registered solver with the input concurrently,
+// each under the timeout, solvers failing to parse the input or scoring
+// it 0 are skipped
+// Returns candidates ordered by score, best first, error if the input
+// can't be read or ctx is done
+func Detect(ctx context.Context, reader io.Reader, timeout time.Duration) ([]Candidate, error) {
+	input, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read input: %v: %w", err, ErrInvalidInput)
+	}
+
+	mu.RLock()
+	items := make([]RegistryItem, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, registry[k])
+	}
+	mu.RUnlock()
+
+	var result []Candidate
+	var resultMu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, item := range items {
+		wg.Add(1)
+		go func(item RegistryItem) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			v, in, err := validate(ctx, item, bytes.NewReader(input))
+
+			if err != nil || !v.Valid() {
+				return
+			}
+
+			score, ok, err := in.score()
+
+			if err != nil {
+				return
+			}
+
+			if !ok {
+				score = defaultScore
+			}
+
+			// parsed but not input of the puzzle
+			if score == 0 {
+				return
+			}
+
+			resultMu.Lock()
+			result = append(result, Candidate{Name: item.Name, Score: score})
+			resultMu.Unlock()
+		}(item)
+	}
+
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, ErrTimeout
+	}
+
+	slices.SortFunc(result, func(a, b Candidate) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+
+		return cmpDays(a.Name, b.Name)
+	})
+
+	return result, nil
+}
+
+// Scores the input, clamped between 0 and 1
+// Panic is returned as PanicError
+func scoreInput(day string, s Scorer) (score float64, err error) {
+	defer recoverPanic(day, "score", 0, &err)
+
+	return min(max(s.Score(), 0), 1), nil
+}
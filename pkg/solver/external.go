@@ -0,0 +1,463 @@
+// Package provides solvers running as external processes
+package solver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Version of the external solver protocol
+const ExternalProtocol = 1
+
+// Time limit of the info request while loading external solvers
+const externalInfoTimeout = 5 * time.Second
+
+// Maximum bytes of the responses to the requests sent at once and of
+// the error output kept of the external solver
+const (
+	maxExternalOutput = 64 * 1024 * 1024
+	maxExternalStderr = 64 * 1024
+)
+
+// Error of the external solver writing more than the limits allow
+var errOutputLimit = errors.New("output limit exceeded")
+
+// Operations of the external solver protocol
+const (
+	OpInfo  = "info"
+	OpInit  = "init"
+	OpSolve = "solve"
+	OpStep  = "step"
+)
+
+// Error kinds of the external solver protocol
+// Mapped to the package errors
+const (
+	KindInvalidInput   = "invalidInput"
+	KindUnknownPart    = "unknownPart"
+	KindTimeout        = "timeout"
+	KindPanic          = "panic"
+	KindUnknownVariant = "unknownVariant"
+	KindInvalidParam   = "invalidParam"
+)
+
+// Request sent to the external solver, one JSON document per line
+// Variant of the solve request is optional, the default is used without it
+// Params of the init request are values of the parameters declared in
+// metadata, missing parameters have their defaults
+// Step request returns states of the next Steps steps of the initialized
+// input
+type ExternalRequest struct {
+	Op      string            `json:"op"`
+	Input   string            `json:"input,omitempty"`
+	Params  map[string]string `json:"params,omitempty"`
+	Part    int               `json:"part,omitempty"`
+	Variant string            `json:"variant,omitempty"`
+	Steps   int               `json:"steps,omitempty"`
+}
+
+// Response of the external solver, one JSON document per line
+// Error is set on failure, Kind classifies the error
+// CPUTime in nanoseconds and Allocated bytes of the solve are optional
+// Facts and Score of the parsed input are optional in the init response,
+// States of the step response are fewer than requested after the last step
+// Line, Column, Expected, Found and Reason position invalid input errors,
+// see InputError
+type ExternalResponse struct {
+	Protocol  int              `json:"protocol,omitempty"`
+	Name      string           `json:"name,omitempty"`
+	Metadata  *Metadata        `json:"metadata,omitempty"`
+	Output    string           `json:"output,omitempty"`
+	Stats     map[string]int64 `json:"stats,omitempty"`
+	CPUTime   int64            `json:"cpuTime,omitempty"`
+	Allocated uint64           `json:"allocated,omitempty"`
+	Facts     map[string]int64 `json:"facts,omitempty"`
+	Score     *float64         `json:"score,omitempty"`
+	States    []string         `json:"states,omitempty"`
+	Error     string           `json:"error,omitempty"`
+	Kind      string           `json:"kind,omitempty"`
+	Line      int              `json:"line,omitempty"`
+	Column    int              `json:"column,omitempty"`
+	Expected  string           `json:"expected,omitempty"`
+	Found     string           `json:"found,omitempty"`
+	Reason    string           `json:"reason,omitempty"`
+}
+
+// Solver running an external executable
+// Every operation runs a new process, the process gets the input
+// again before solving a part, so no state is kept between processes
+type externalSolver struct {
+	run      ExternalRunner
+	metadata Metadata
+	input    string
+	params   map[string]string
+}
+
+// Runs one process of an external solver
+// The process reads requests from stdin and writes responses to stdout,
+// it has to be stopped when ctx is done
+type ExternalRunner func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer) error
+
+// Loads executables in the directory as external solvers
+// Solvers are registered under the name reported by the executable,
+// names of already registered solvers are not replaced
+// Returns registered names and errors of the executables which failed to load
+func LoadExternal(ctx context.Context, dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read solver directory %s: %w", dir, err)
+	}
+
+	var names []string
+	var errs []error
+
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		fileInfo, err := e.Info()
+
+		if err != nil || fileInfo.Mode()&0111 == 0 {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+
+		name, err := RegisterExternal(ctx, path, ExecRunner(path))
+
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	return names, errors.Join(errs...)
+}
+
+// Registers the external solver run by the runner
+// Name and metadata are reported by the solver, name defaults to the base
+// name of the path without extension, path is used in errors only
+// Names of already registered solvers are not replaced
+// Returns registered name
+func RegisterExternal(ctx context.Context, path string, run ExternalRunner) (string, error) {
+	info, err := externalInfo(ctx, run)
+
+	if err != nil {
+		return "", fmt.Errorf("external solver %s: %w", path, err)
+	}
+
+	name := info.Name
+
+	if name == "" {
+		base := filepath.Base(path)
+		name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	metadata := defaultMetadata(name)
+
+	if info.Metadata != nil {
+		metadata = *info.Metadata
+	}
+
+	if _, ok := Lookup(name); ok {
+		return "", fmt.Errorf("external solver %s: %s already registered", path, name)
+	}
+
+	Register(name, func() PuzzleSolver {
+		return &externalSolver{run: run, metadata: metadata}
+	})
+
+	return name, nil
+}
+
+// Asks the solver for its name and metadata
+func externalInfo(ctx context.Context, run ExternalRunner) (ExternalResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, externalInfoTimeout)
+	defer cancel()
+
+	responses, err := runExternal(ctx, run, ExternalRequest{Op: OpInfo})
+
+	if err != nil {
+		return ExternalResponse{}, err
+	}
+
+	if responses[0].Protocol != ExternalProtocol {
+		return ExternalResponse{}, fmt.Errorf("unsupported protocol %d", responses[0].Protocol)
+	}
+
+	return responses[0], nil
+}
+
+// Returns runner of the executable started with the arguments
+// Killing the process on context cancellation is left to exec.CommandContext
+func ExecRunner(path string, args ...string) ExternalRunner {
+	return func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer) error {
+		cmd := exec.CommandContext(ctx, path, args...)
+		cmd.Stdin = stdin
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+
+		// processes left behind by the executable can't block the solver
+		cmd.WaitDelay = time.Second
+
+		return cmd.Run()
+	}
+}
+
+// Runs the solver, sends the requests and reads one response per request
+// Returns responses or error of the first failed request
+func runExternal(ctx context.Context, run ExternalRunner, requests ...ExternalRequest) ([]ExternalResponse, error) {
+	var stdin bytes.Buffer
+
+	stdout := cappedWriter{limit: maxExternalOutput}
+	stderr := cappedWriter{limit: maxExternalStderr}
+
+	enc := json.NewEncoder(&stdin)
+
+	for _, r := range requests {
+		if err := enc.Encode(r); err != nil {
+			return nil, fmt.Errorf("unable to encode request: %w", err)
+		}
+	}
+
+	err := run(ctx, &stdin, &stdout, &stderr)
+
+	if ctx.Err() != nil || errors.Is(err, ErrTimeout) {
+		return nil, ErrTimeout
+	}
+
+	if stdout.exceeded || stderr.exceeded {
+		return nil, fmt.Errorf("external solver: %w", errOutputLimit)
+	}
+
+	responses := make([]ExternalResponse, 0, len(requests))
+	dec := json.NewDecoder(&stdout.buf)
+
+	for range requests {
+		var r ExternalResponse
+
+		if decErr := dec.Decode(&r); decErr != nil {
+			if err != nil {
+				return nil, fmt.Errorf("process failed: %w: %s", err, strings.TrimSpace(stderr.buf.String()))
+			}
+
+			return nil, fmt.Errorf("unable to decode response: %w", decErr)
+		}
+
+		if r.Error != "" {
+			return nil, externalError(r)
+		}
+
+		responses = append(responses, r)
+	}
+
+	return responses, nil
+}
+
+// Buffer failing writes which exceed the limit
+type cappedWriter struct {
+	buf      bytes.Buffer
+	limit    int
+	exceeded bool
+}
+
+// Writes p, fails without writing once the limit would be exceeded
+func (w *cappedWriter) Write(p []byte) (int, error) {
+	if w.exceeded || w.buf.Len()+len(p) > w.limit {
+		w.exceeded = true
+		return 0, errOutputLimit
+	}
+
+	return w.buf.Write(p)
+}
+
+// Reader failing once more than the limit was read
+type cappedReader struct {
+	r     io.Reader
+	limit int
+}
+
+// Reads up to the remaining limit, fails after the limit was read
+func (r *cappedReader) Read(p []byte) (int, error) {
+	if r.limit <= 0 {
+		return 0, errOutputLimit
+	}
+
+	if len(p) > r.limit {
+		p = p[:r.limit]
+	}
+
+	n, err := r.r.Read(p)
+	r.limit -= n
+
+	return n, err
+}
+
+// Converts error response to error wrapping package error of its kind
+func externalError(r ExternalResponse) error {
+	switch r.Kind {
+	case KindInvalidInput:
+		if ie := r.inputError(); ie != nil {
+			// keeps e.g. the day the child prefixed the error with
+			if prefix, ok := strings.CutSuffix(r.Error, ie.Error()); ok {
+				return fmt.Errorf("%s%w", prefix, ie)
+			}
+
+			return fmt.Errorf("%s: %w", r.Error, ie)
+		}
+
+		return fmt.Errorf("%s: %w", r.Error, ErrInvalidInput)
+	case KindUnknownPart:
+		return fmt.Errorf("%s: %w", r.Error, ErrUnknownPart)
+	case KindTimeout:
+		return fmt.Errorf("%s: %w", r.Error, ErrTimeout)
+	case KindPanic:
+		return fmt.Errorf("%s: %w", r.Error, ErrSolverPanic)
+	case KindUnknownVariant:
+		return fmt.Errorf("%s: %w", r.Error, ErrUnknownVariant)
+	case KindInvalidParam:
+		return fmt.Errorf("%s: %w", r.Error, ErrInvalidParam)
+	}
+
+	return errors.New(r.Error)
+}
+
+// Returns positioned input error of the response, nil without position
+func (r ExternalResponse) inputError() *InputError {
+	ie := InputError{Line: r.Line, Column: r.Column, Expected: r.Expected, Found: r.Found, Reason: r.Reason}
+
+	if ie == (InputError{}) {
+		return nil
+	}
+
+	return &ie
+}
+
+// Returns error response of the error, positioned input errors keep
+// their position
+func externalErrorResponse(err error) ExternalResponse {
+	resp := ExternalResponse{Error: err.Error(), Kind: externalKind(err)}
+
+	var ie *InputError
+
+	if errors.As(err, &ie) {
+		resp.Line, resp.Column, resp.Expected, resp.Found, resp.Reason = ie.Line, ie.Column, ie.Expected, ie.Found, ie.Reason
+	}
+
+	return resp
+}
+
+// Returns kind of the error for the error response
+func externalKind(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		return KindInvalidInput
+	case errors.Is(err, ErrUnknownPart):
+		return KindUnknownPart
+	case errors.Is(err, ErrTimeout):
+		return KindTimeout
+	case errors.Is(err, ErrSolverPanic):
+		return KindPanic
+	case errors.Is(err, ErrUnknownVariant):
+		return KindUnknownVariant
+	case errors.Is(err, ErrInvalidParam):
+		return KindInvalidParam
+	}
+
+	return ""
+}
+
+// Returns description of the puzzle reported by the executable
+func (p *externalSolver) Metadata() Metadata {
+	return p.metadata
+}
+
+// Sets parameters sent with the input, they are validated by the executable
+func (p *externalSolver) SetParams(params Params) error {
+	p.params = params.Strings()
+
+	return nil
+}
+
+// Initializes the solver, input is validated by the executable
+func (p *externalSolver) Init(reader io.Reader) error {
+	return p.InitCtx(context.Background(), reader)
+}
+
+// Solves the puzzle
+func (p *externalSolver) Solve(part int) (string, error) {
+	return p.SolveCtx(context.Background(), part)
+}
+
+// Initializes the solver, input is validated by the executable
+func (p *externalSolver) InitCtx(ctx context.Context, reader io.Reader) error {
+	b, err := io.ReadAll(reader)
+
+	if err != nil {
+		return fmt.Errorf("unable to read input: %w", err)
+	}
+
+	if _, err := runExternal(ctx, p.run, ExternalRequest{Op: OpInit, Input: string(b), Params: p.params}); err != nil {
+		return err
+	}
+
+	p.input = string(b)
+
+	return nil
+}
+
+// Solves the puzzle
+func (p *externalSolver) SolveCtx(ctx context.Context, part int) (string, error) {
+	result, err := p.SolveResult(ctx, part)
+
+	if err != nil {
+		return "", err
+	}
+
+	return result.Output, nil
+}
+
+// Solves the puzzle, result carries statistics and usage reported by
+// the executable
+func (p *externalSolver) SolveResult(ctx context.Context, part int) (Result, error) {
+	return p.solve(ctx, part, "")
+}
+
+// Solves the part with the variant reported in metadata of the executable
+func (p *externalSolver) SolveVariant(ctx context.Context, part int, variant string) (string, error) {
+	result, err := p.solve(ctx, part, variant)
+
+	if err != nil {
+		return "", err
+	}
+
+	return result.Output, nil
+}
+
+// Solves the part with the variant, empty variant is the default
+func (p *externalSolver) solve(ctx context.Context, part int, variant string) (Result, error) {
+	responses, err := runExternal(ctx, p.run,
+		ExternalRequest{Op: OpInit, Input: p.input, Params: p.params},
+		ExternalRequest{Op: OpSolve, Part: part, Variant: variant})
+
+	if err != nil {
+		return Result{}, err
+	}
+
+	r := responses[1]
+
+	return Result{Output: r.Output, Stats: r.Stats, CPUTime: time.Duration(r.CPUTime), Allocated: r.Allocated}, nil
+}
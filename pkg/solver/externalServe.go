@@ -0,0 +1,144 @@
+// Package provides serving of solvers over the external solver protocol
+package solver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Maximum size of a request line, has to fit the puzzle input
+const maxExternalRequest = 64 * 1024 * 1024
+
+// Serves the solver over the external solver protocol
+// Reads requests from r and writes one response per request to w until
+// r is closed, panics of the solver are returned as errors of kind panic
+// Allows writing external solvers in Go:
+//
+//	func main() {
+//		solver.ServeExternal(os.Stdin, os.Stdout, "d42", func() solver.PuzzleSolver { return NewSolver() })
+//	}
+func ServeExternal(r io.Reader, w io.Writer, name string, constructor func() PuzzleSolver) error {
+	ctx := context.Background()
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxExternalRequest)
+
+	enc := json.NewEncoder(w)
+
+	metadata := defaultMetadata(name)
+
+	if d, ok := constructor().(Documented); ok {
+		metadata = d.Metadata()
+	}
+
+	var s PuzzleSolverWithCtx
+
+	for sc.Scan() {
+		var req ExternalRequest
+		var resp ExternalResponse
+
+		err := json.Unmarshal(sc.Bytes(), &req)
+
+		switch {
+		case err != nil:
+			err = fmt.Errorf("unable to decode request: %w", err)
+		case req.Op == OpInfo:
+			resp = ExternalResponse{Protocol: ExternalProtocol, Name: name, Metadata: &metadata}
+		case req.Op == OpInit:
+			s = nil
+
+			var params Params
+			params, err = metadata.ParseParams(req.Params)
+
+			if err != nil {
+				break
+			}
+
+			s = guard(name, WithCtx(constructor()))
+			err = s.InitCtx(WithParams(ctx, params), strings.NewReader(req.Input))
+
+			if err == nil {
+				resp, err = inspectInput(s.(inspector))
+			}
+
+			if err != nil {
+				s = nil
+			}
+		case (req.Op == OpSolve || req.Op == OpStep) && s == nil:
+			err = fmt.Errorf("solver not initialized")
+		case req.Op == OpSolve:
+			var result Result
+			result, err = SolveVariant(ctx, s, req.Part, req.Variant)
+			resp = ExternalResponse{Output: result.Output, Stats: result.Stats, CPUTime: int64(result.CPUTime), Allocated: result.Allocated}
+		case req.Op == OpStep:
+			resp.States, err = serveSteps(ctx, s, req.Steps)
+		default:
+			err = fmt.Errorf("unknown operation %s", req.Op)
+		}
+
+		if err != nil {
+			resp = externalErrorResponse(err)
+		}
+
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("unable to encode response: %w", err)
+		}
+	}
+
+	return sc.Err()
+}
+
+// Returns init response with facts and score of the parsed input
+func inspectInput(in inspector) (ExternalResponse, error) {
+	var resp ExternalResponse
+	var err error
+
+	if resp.Facts, err = in.describe(); err != nil {
+		return ExternalResponse{}, err
+	}
+
+	score, ok, err := in.score()
+
+	if err != nil {
+		return ExternalResponse{}, err
+	}
+
+	if ok {
+		resp.Score = &score
+	}
+
+	return resp, nil
+}
+
+// Returns states of up to count next steps, fewer once the solver has
+// no more steps
+func serveSteps(ctx context.Context, s PuzzleSolverWithCtx, count int) ([]string, error) {
+	st, ok := s.(StepperWithCtx)
+
+	if !ok {
+		return nil, fmt.Errorf("stepwise solving not supported")
+	}
+
+	states := make([]string, 0, count)
+
+	for range count {
+		state, err := st.Next(ctx)
+
+		if errors.Is(err, ErrNoMoreSteps) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		states = append(states, state)
+	}
+
+	return states, nil
+}
@@ -0,0 +1,121 @@
+// Package provides sessions of requests to one external solver process
+package solver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Session of requests sent to one process of the external solver
+// The process runs until the session is closed or ctx it was started
+// with is done, every response is limited like the responses of
+// runExternal
+type externalSession struct {
+	stdin  *io.PipeWriter
+	out    *io.PipeReader
+	stdout *cappedReader
+	enc    *json.Encoder
+	dec    *json.Decoder
+	stderr cappedWriter
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// Response of the session or error reading it
+type sessionReply struct {
+	resp ExternalResponse
+	err  error
+}
+
+// Starts the process of the session
+func startExternalSession(ctx context.Context, run ExternalRunner) *externalSession {
+	ctx, cancel := context.WithCancel(ctx)
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+
+	s := &externalSession{
+		stdin:  inW,
+		out:    outR,
+		stdout: &cappedReader{r: outR},
+		enc:    json.NewEncoder(inW),
+		stderr: cappedWriter{limit: maxExternalStderr},
+		cancel: cancel,
+		done:   make(chan error, 1),
+	}
+
+	s.dec = json.NewDecoder(s.stdout)
+
+	go func() {
+		err := run(ctx, inR, outW, &s.stderr)
+
+		// pending requests and responses fail once the process is gone
+		inR.Close()
+		outW.Close()
+
+		s.done <- err
+	}()
+
+	return s
+}
+
+// Sends the request and returns its response, the process is killed
+// when ctx is done
+func (s *externalSession) request(ctx context.Context, req ExternalRequest) (ExternalResponse, error) {
+	replies := make(chan sessionReply, 2)
+
+	s.stdout.limit = maxExternalOutput
+
+	// the response is read even when the process does not read requests
+	go func() {
+		if err := s.enc.Encode(req); err != nil {
+			replies <- sessionReply{err: err}
+		}
+	}()
+
+	go func() {
+		var r sessionReply
+		r.err = s.dec.Decode(&r.resp)
+		replies <- r
+	}()
+
+	var r sessionReply
+
+	select {
+	case <-ctx.Done():
+		s.close()
+		return ExternalResponse{}, ErrTimeout
+	case r = <-replies:
+	}
+
+	if r.err != nil {
+		s.close()
+
+		if errors.Is(r.err, errOutputLimit) {
+			return ExternalResponse{}, fmt.Errorf("external solver: %w", errOutputLimit)
+		}
+
+		if err := <-s.done; err != nil {
+			return ExternalResponse{}, fmt.Errorf("process failed: %w: %s", err, strings.TrimSpace(s.stderr.buf.String()))
+		}
+
+		return ExternalResponse{}, fmt.Errorf("unable to decode response: %w", r.err)
+	}
+
+	if r.resp.Error != "" {
+		return ExternalResponse{}, externalError(r.resp)
+	}
+
+	return r.resp, nil
+}
+
+// Closes stdin and stdout of the process and kills it
+func (s *externalSession) close() {
+	s.stdin.Close()
+	s.out.Close()
+	s.cancel()
+}
@@ -0,0 +1,464 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Number of inputs parsed by parentOnlySolver
+var parentInits atomic.Int32
+
+// In-process solver of isolation tests, answers differ from the child
+type parentOnlySolver struct {
+	plainSolver
+}
+
+func (p *parentOnlySolver) Init(reader io.Reader) error {
+	parentInits.Add(1)
+
+	return p.plainSolver.Init(reader)
+}
+
+func (p *parentOnlySolver) Next() (string, error) {
+	return "parent", nil
+}
+
+func (p *parentOnlySolver) Describe() map[string]int64 {
+	return map[string]int64{"parent": 1}
+}
+
+func (p *parentOnlySolver) Score() float64 {
+	return 0.1
+}
+
+// Environment variable turning the test binary into an external solver
+const externalChildEnv = "SOLVER_EXTERNAL_CHILD"
+
+// Solver served by the test binary
+// Part 1 returns length of the input times repeat, part 2 never finishes
+// Steps are numbered up to length of the input, followed by the process id
+type externalChildSolver struct {
+	input  string
+	repeat int
+	steps  int
+}
+
+func (p *externalChildSolver) SetParams(params Params) error {
+	p.repeat = params.Int("repeat")
+
+	return nil
+}
+
+func (p *externalChildSolver) Init(reader io.Reader) error {
+	b, _ := io.ReadAll(reader)
+
+	if string(b) == "invalid" {
+		return fmt.Errorf("child %w", &InputError{Line: 1, Column: 2, Expected: "stone", Found: "nvalid", Reason: "not a stone"})
+	}
+
+	p.input = string(b)
+
+	return nil
+}
+
+func (p *externalChildSolver) Solve(part int) (string, error) {
+	switch part {
+	case 1:
+		return strconv.Itoa(len(p.input) * max(p.repeat, 1)), nil
+	case 2:
+		time.Sleep(time.Minute)
+		return "", nil
+	}
+
+	return "", fmt.Errorf("child unknown part %d: %w", part, ErrUnknownPart)
+}
+
+func (p *externalChildSolver) Next() (string, error) {
+	if p.steps == len(p.input) {
+		return "", ErrNoMoreSteps
+	}
+
+	p.steps++
+
+	return fmt.Sprintf("%d %d", p.steps, os.Getpid()), nil
+}
+
+func (p *externalChildSolver) Describe() map[string]int64 {
+	return map[string]int64{"length": int64(len(p.input))}
+}
+
+func (p *externalChildSolver) Score() float64 {
+	return 0.5
+}
+
+func (p *externalChildSolver) Metadata() Metadata {
+	return Metadata{
+		Title:  "External",
+		Parts:  []Part{{Part: 1, Label: "Length"}, {Part: 2, Label: "Forever"}},
+		Params: []Param{{Name: "repeat", Type: ParamInt, Default: "1", Min: 1, Max: 3}},
+	}
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv(externalChildEnv) != "" {
+		err := ServeExternal(os.Stdin, os.Stdout, os.Getenv(externalChildEnv), func() PuzzleSolver { return &externalChildSolver{} })
+
+		if err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// Creates directory with a script running the test binary as external solver
+func externalDir(t *testing.T, name string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("external solver test requires sh")
+	}
+
+	exe, err := os.Executable()
+
+	if err != nil {
+		t.Fatalf("unable to find test binary: %v", err)
+	}
+
+	dir := t.TempDir()
+	script := fmt.Sprintf("#!/bin/sh\n%s=%s exec %q\n", externalChildEnv, name, exe)
+
+	if err := os.WriteFile(filepath.Join(dir, "solver.sh"), []byte(script), 0755); err != nil {
+		t.Fatalf("unable to write script: %v", err)
+	}
+
+	// not executable, skipped
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("docs"), 0644); err != nil {
+		t.Fatalf("unable to write readme: %v", err)
+	}
+
+	return dir
+}
+
+func TestExternal(t *testing.T) {
+	dir := externalDir(t, "test-external")
+
+	names, err := LoadExternal(context.Background(), dir)
+
+	if err != nil || len(names) != 1 || names[0] != "test-external" {
+		t.Fatalf("got %v, %v expected [test-external]", names, err)
+	}
+
+	t.Run("listed", func(t *testing.T) {
+		item, ok := Lookup("test-external")
+
+		if !ok || !item.Capabilities.Ctx || item.Metadata.Title != "External" {
+			t.Errorf("got %+v", item)
+		}
+	})
+
+	t.Run("solve", func(t *testing.T) {
+		s, _ := NewWithCtx("test-external")
+
+		if err := s.InitCtx(context.Background(), strings.NewReader("input")); err != nil {
+			t.Fatalf("got %v expected nil", err)
+		}
+
+		got, err := s.SolveCtx(context.Background(), 1)
+
+		if err != nil || got != "5" {
+			t.Errorf("got %s, %v expected 5", got, err)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		s, _ := NewWithCtx("test-external")
+
+		err := s.InitCtx(context.Background(), strings.NewReader("invalid"))
+
+		var ie *InputError
+
+		if !errors.As(err, &ie) || ie.Line != 1 || ie.Column != 2 || ie.Found != "nvalid" {
+			t.Errorf("got %v expected positioned %v", err, ErrInvalidInput)
+		}
+
+		if want := "child line 1, column 2: not a stone \"nvalid\": invalid input"; err.Error() != want {
+			t.Errorf("got %q expected %q", err, want)
+		}
+	})
+
+	t.Run("unknown part", func(t *testing.T) {
+		s, _ := NewWithCtx("test-external")
+		_ = s.InitCtx(context.Background(), strings.NewReader("input"))
+
+		if _, err := s.SolveCtx(context.Background(), 3); !errors.Is(err, ErrUnknownPart) {
+			t.Errorf("got %v expected %v", err, ErrUnknownPart)
+		}
+	})
+
+	t.Run("cancellation kills the process", func(t *testing.T) {
+		s, _ := NewWithCtx("test-external")
+		_ = s.InitCtx(context.Background(), strings.NewReader("input"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := s.SolveCtx(ctx, 2)
+
+		if !errors.Is(err, ErrTimeout) {
+			t.Errorf("got %v expected %v", err, ErrTimeout)
+		}
+
+		if time.Since(start) > 10*time.Second {
+			t.Errorf("process was not killed")
+		}
+	})
+
+	t.Run("params", func(t *testing.T) {
+		item, _ := Lookup("test-external")
+		params, _ := item.Metadata.ParseParams(map[string]string{"repeat": "2"})
+
+		s, _ := NewWithCtx("test-external")
+
+		if err := s.InitCtx(WithParams(context.Background(), params), strings.NewReader("input")); err != nil {
+			t.Fatalf("got %v expected nil", err)
+		}
+
+		got, err := s.SolveCtx(context.Background(), 1)
+
+		if err != nil || got != "10" {
+			t.Errorf("got %s, %v expected 10", got, err)
+		}
+	})
+
+	t.Run("params rejected by the executable", func(t *testing.T) {
+		s, _ := NewWithCtx("test-external")
+
+		err := s.InitCtx(WithParams(context.Background(), Params{"repeat": 7}), strings.NewReader("input"))
+
+		if !errors.Is(err, ErrInvalidParam) {
+			t.Errorf("got %v expected %v", err, ErrInvalidParam)
+		}
+	})
+
+	t.Run("already registered", func(t *testing.T) {
+		if _, err := LoadExternal(context.Background(), dir); err == nil {
+			t.Errorf("got nil expected error")
+		}
+	})
+}
+
+func TestExternalOutputLimit(t *testing.T) {
+	// writes n bytes to the selected stream, returns once the write failed
+	flooding := func(n int, toStderr bool) ExternalRunner {
+		return func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer) error {
+			w := stdout
+			if toStderr {
+				w = stderr
+			}
+
+			_, err := io.Copy(w, io.LimitReader(spaces{}, int64(n)))
+			return err
+		}
+	}
+
+	cases := []struct {
+		name string
+		run  ExternalRunner
+	}{
+		{"stdout", flooding(maxExternalOutput+1, false)},
+		{"stderr", flooding(maxExternalStderr+1, true)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := runExternal(context.Background(), c.run, ExternalRequest{Op: OpInfo})
+
+			if !errors.Is(err, errOutputLimit) {
+				t.Errorf("got %v expected %v", err, errOutputLimit)
+			}
+		})
+	}
+
+	t.Run("session", func(t *testing.T) {
+		s := startExternalSession(context.Background(), flooding(maxExternalOutput+1, false))
+		defer s.close()
+
+		_, err := s.request(context.Background(), ExternalRequest{Op: OpInfo})
+
+		if !errors.Is(err, errOutputLimit) {
+			t.Errorf("got %v expected %v", err, errOutputLimit)
+		}
+	})
+}
+
+// Reader of endless spaces, whitespace between JSON documents
+type spaces struct{}
+
+func (spaces) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = ' '
+	}
+
+	return len(p), nil
+}
+
+func TestIsolate(t *testing.T) {
+	script := filepath.Join(externalDir(t, "test-isolated"), "solver.sh")
+
+	// in-process solver returns the input, the child its length
+	Register("test-isolated", func() PuzzleSolver { return &plainSolver{} })
+
+	Isolate(func(name string) ExternalRunner { return ExecRunner(script) })
+	defer Isolate(nil)
+
+	t.Run("solve", func(t *testing.T) {
+		s, _ := NewWithCtx("test-isolated")
+
+		if err := s.InitCtx(context.Background(), strings.NewReader("input")); err != nil {
+			t.Fatalf("got %v expected nil", err)
+		}
+
+		got, err := s.SolveCtx(context.Background(), 1)
+
+		if err != nil || got != "5" {
+			t.Errorf("got %s, %v expected 5", got, err)
+		}
+	})
+
+	t.Run("deadline kills the child", func(t *testing.T) {
+		s, _ := NewWithCtx("test-isolated")
+		_ = s.InitCtx(context.Background(), strings.NewReader("input"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := s.SolveCtx(ctx, 2)
+
+		if !errors.Is(err, ErrTimeout) {
+			t.Errorf("got %v expected %v", err, ErrTimeout)
+		}
+
+		if time.Since(start) > 10*time.Second {
+			t.Errorf("child was not killed")
+		}
+	})
+
+	t.Run("params are sent to the child", func(t *testing.T) {
+		script := filepath.Join(externalDir(t, "test-isolated-params"), "solver.sh")
+
+		Register("test-isolated-params", func() PuzzleSolver { return &externalChildSolver{} })
+
+		Isolate(func(name string) ExternalRunner { return ExecRunner(script) })
+
+		s, _ := NewWithCtx("test-isolated-params")
+		_ = s.InitCtx(WithParams(context.Background(), Params{"repeat": 3}), strings.NewReader("input"))
+
+		got, err := s.SolveCtx(context.Background(), 1)
+
+		if err != nil || got != "15" {
+			t.Errorf("got %s, %v expected 15", got, err)
+		}
+	})
+
+	t.Run("input is parsed in the child", func(t *testing.T) {
+		script := filepath.Join(externalDir(t, "test-isolated-parent"), "solver.sh")
+
+		Register("test-isolated-parent", func() PuzzleSolver { return &parentOnlySolver{} })
+
+		Isolate(func(name string) ExternalRunner { return ExecRunner(script) })
+
+		before := parentInits.Load()
+
+		s, _ := NewWithCtx("test-isolated-parent")
+
+		if err := s.InitCtx(context.Background(), strings.NewReader("input")); err != nil {
+			t.Fatalf("got %v expected nil", err)
+		}
+
+		v, err := Validate(context.Background(), "test-isolated-parent", strings.NewReader("input"))
+
+		if err != nil || !maps.Equal(v.Facts, map[string]int64{"length": 5}) {
+			t.Errorf("got %v, %v expected facts of the child", v.Facts, err)
+		}
+
+		t.Run("invalid input keeps its position", func(t *testing.T) {
+			err := s.InitCtx(context.Background(), strings.NewReader("invalid"))
+
+			var ie *InputError
+
+			if !errors.As(err, &ie) || ie.Line != 1 || ie.Column != 2 {
+				t.Errorf("got %v expected positioned %v", err, ErrInvalidInput)
+			}
+		})
+
+		if n := parentInits.Load(); n != before {
+			t.Errorf("got %d inputs parsed in-process expected 0", n-before)
+		}
+
+		t.Run("score of the child", func(t *testing.T) {
+			got, _ := Detect(context.Background(), strings.NewReader("input"), 10*time.Second)
+
+			if !slices.Contains(got, Candidate{"test-isolated-parent", 0.5}) {
+				t.Errorf("got %+v expected test-isolated-parent with score 0.5", got)
+			}
+		})
+
+		t.Run("steps in the child", func(t *testing.T) {
+			s, _ := NewWithCtx("test-isolated-parent")
+			_ = s.InitCtx(context.Background(), strings.NewReader("input"))
+
+			st, ok := s.(StepperWithCtx)
+
+			if !ok {
+				t.Fatalf("isolated solver is not stepper")
+			}
+
+			pids := map[string]bool{}
+
+			for i := 1; i <= 5; i++ {
+				got, err := st.Next(context.Background())
+				step, pid, _ := strings.Cut(got, " ")
+
+				if err != nil || step != strconv.Itoa(i) {
+					t.Fatalf("got %s, %v expected step %d", got, err, i)
+				}
+
+				pids[pid] = true
+			}
+
+			if len(pids) != 1 {
+				t.Errorf("got steps of %d children expected 1", len(pids))
+			}
+
+			if _, err := st.Next(context.Background()); !errors.Is(err, ErrNoMoreSteps) {
+				t.Errorf("got %v expected %v", err, ErrNoMoreSteps)
+			}
+		})
+	})
+
+	t.Run("external solvers run in-process", func(t *testing.T) {
+		item, _ := Lookup("test-isolated")
+
+		if isolatedRunner(item.Name, WithCtx(item.Constructor())) == nil {
+			t.Errorf("built-in solver not isolated")
+		}
+
+		if isolatedRunner("external", &externalSolver{}) != nil {
+			t.Errorf("external solver isolated")
+		}
+	})
+}
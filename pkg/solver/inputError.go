@@ -0,0 +1,55 @@
+// Package provides positioned errors of the puzzle input
+package solver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Maximum length of the offending text in the error message
+const maxErrorText = 80
+
+// Error of the puzzle input
+// Line and Column are 1-based, Column is 0 when the whole line is wrong,
+// Line is 0 when the whole input is wrong
+// Expected describes valid input, Found is the offending text, both may be empty
+type InputError struct {
+	Line     int
+	Column   int
+	Expected string
+	Found    string
+	Reason   string
+}
+
+// Returns e.g. line 3, column 5: not a number "x1": invalid input
+func (e *InputError) Error() string {
+	var sb strings.Builder
+
+	switch {
+	case e.Line == 0:
+	case e.Column == 0:
+		fmt.Fprintf(&sb, "line %d: ", e.Line)
+	default:
+		fmt.Fprintf(&sb, "line %d, column %d: ", e.Line, e.Column)
+	}
+
+	sb.WriteString(e.Reason)
+
+	if e.Found != "" {
+		text := e.Found
+		if len(text) > maxErrorText {
+			text = text[:maxErrorText] + "..."
+		}
+
+		fmt.Fprintf(&sb, " %q", text)
+	}
+
+	fmt.Fprintf(&sb, ": %v", ErrInvalidInput)
+
+	return sb.String()
+}
+
+// Unwraps to ErrInvalidInput
+func (e *InputError) Unwrap() error {
+	return ErrInvalidInput
+}
@@ -0,0 +1,88 @@
+// Package provides overflow-safe integer arithmetic of the answers
+package solver
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// Adds a and b, ok is false if the sum overflows int
+func AddInt(a, b int) (sum int, ok bool) {
+	sum = a + b
+
+	return sum, (sum > a) == (b > 0)
+}
+
+// Multiplies a and b, ok is false if the product overflows int
+func MulInt(a, b int) (product int, ok bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	product = a * b
+
+	if (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) {
+		return product, false
+	}
+
+	return product, product/b == a
+}
+
+// Returns typed value of the integer like ParseValue,
+// int64 if it fits, *big.Int otherwise
+func IntValue(v *big.Int) any {
+	if v.IsInt64() {
+		return v.Int64()
+	}
+
+	return v
+}
+
+// Sum of integers kept in int until it overflows, then in big.Int
+// Zero value is an empty sum
+type Sum struct {
+	small int
+	large *big.Int
+}
+
+// Adds v to the sum
+func (s *Sum) Add(v int) {
+	if s.large == nil {
+		if sum, ok := AddInt(s.small, v); ok {
+			s.small = sum
+			return
+		}
+
+		s.large = big.NewInt(int64(s.small))
+	}
+
+	s.large.Add(s.large, big.NewInt(int64(v)))
+}
+
+// Returns the sum as big.Int
+func (s *Sum) Big() *big.Int {
+	if s.large == nil {
+		return big.NewInt(int64(s.small))
+	}
+
+	return new(big.Int).Set(s.large)
+}
+
+// Returns typed value of the sum, int64 or *big.Int
+func (s *Sum) Value() any {
+	if s.large == nil {
+		return int64(s.small)
+	}
+
+	return IntValue(s.large)
+}
+
+// Returns the sum in decimal
+func (s *Sum) String() string {
+	if s.large == nil {
+		return strconv.Itoa(s.small)
+	}
+
+	return s.large.String()
+}
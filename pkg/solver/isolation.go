@@ -0,0 +1,176 @@
+// Package provides solving of in-process solvers in child processes
+package solver
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+)
+
+// Maximum number of steps requested from the child at once
+const maxStepBatch = 1024
+
+// Returns runner of the child process serving the named solver
+var isolation atomic.Pointer[func(name string) ExternalRunner]
+
+// Initializes, solves and steps in-process solvers in child processes
+// started by the runner returned for the solver name, the child has to
+// serve the solver over the external solver protocol, e.g. with
+// ServeExternal
+// Input is never parsed in-process, input errors of the child keep their
+// position
+// Progress of the child is not reported, nil solves in-process again
+func Isolate(runner func(name string) ExternalRunner) {
+	if runner == nil {
+		isolation.Store(nil)
+		return
+	}
+
+	isolation.Store(&runner)
+}
+
+// Returns runner of the child process solving the solver, nil if the
+// solver is not isolated, external solvers already run outside
+func isolatedRunner(name string, s PuzzleSolverWithCtx) ExternalRunner {
+	runner := isolation.Load()
+
+	if runner == nil {
+		return nil
+	}
+
+	if _, ok := s.(*externalSolver); ok {
+		return nil
+	}
+
+	return (*runner)(name)
+}
+
+// State of the isolated solver kept between child processes
+// Facts and score of the input are reported by the child initializing
+// it, states are of the steps made by the stepping child which were not
+// returned yet, err is the error which ended the stepping
+type isolatedState struct {
+	input   string
+	facts   map[string]int64
+	score   *float64
+	session *externalSession
+	states  []string
+	stepped int
+	last    bool
+	err     error
+}
+
+// Returns init request of the child with the kept input and parameters
+func (g *guarded) initRequest() ExternalRequest {
+	return ExternalRequest{Op: OpInit, Input: g.isolated.input, Params: g.params.Strings()}
+}
+
+// Initializes the solver in a child process with the input and keeps
+// the input for the children solving and stepping it
+func (g *guarded) initIsolated(ctx context.Context, reader io.Reader) error {
+	b, err := io.ReadAll(&ctxReader{ctx, reader})
+
+	if ctx.Err() != nil {
+		return ErrTimeout
+	}
+
+	if err != nil {
+		return err
+	}
+
+	g.closeIsolated()
+	g.isolated = isolatedState{input: string(b)}
+
+	responses, err := runExternal(ctx, g.run, g.initRequest())
+
+	if err != nil {
+		g.isolated = isolatedState{}
+		return err
+	}
+
+	g.isolated.facts = responses[0].Facts
+	g.isolated.score = responses[0].Score
+
+	return nil
+}
+
+// Solves the part in a child process initialized with the kept input
+// The child is killed when ctx is done
+func (g *guarded) solveIsolated(ctx context.Context, part int) (Result, error) {
+	child := externalSolver{run: g.run, input: g.isolated.input, params: g.params.Strings()}
+
+	return child.SolveResult(ctx, part)
+}
+
+// Returns state of the next step made in a child process
+// One child initialized on the first step makes all the steps, in
+// batches doubling in size, it runs until the last step or until ctx of
+// the first step is done
+func (g *guarded) nextIsolated(ctx context.Context) (string, error) {
+	s := &g.isolated
+
+	if len(s.states) == 0 {
+		if s.err != nil {
+			return "", s.err
+		}
+
+		if s.last {
+			return "", ErrNoMoreSteps
+		}
+
+		if err := g.stepIsolated(ctx); err != nil {
+			g.closeIsolated()
+			s.err = err
+			return "", err
+		}
+
+		if s.last {
+			g.closeIsolated()
+		}
+
+		if len(s.states) == 0 {
+			return "", ErrNoMoreSteps
+		}
+	}
+
+	state := s.states[0]
+	s.states = s.states[1:]
+	s.stepped++
+
+	return state, nil
+}
+
+// Requests next batch of steps from the stepping child, starts the child
+// on the first step
+func (g *guarded) stepIsolated(ctx context.Context) error {
+	s := &g.isolated
+
+	if s.session == nil {
+		s.session = startExternalSession(ctx, g.run)
+
+		if _, err := s.session.request(ctx, g.initRequest()); err != nil {
+			return err
+		}
+	}
+
+	batch := min(max(s.stepped, 1), maxStepBatch)
+
+	resp, err := s.session.request(ctx, ExternalRequest{Op: OpStep, Steps: batch})
+
+	if err != nil {
+		return err
+	}
+
+	s.states = resp.States
+	s.last = len(s.states) < batch
+
+	return nil
+}
+
+// Stops the stepping child
+func (g *guarded) closeIsolated() {
+	if g.isolated.session != nil {
+		g.isolated.session.close()
+		g.isolated.session = nil
+	}
+}
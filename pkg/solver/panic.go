@@ -0,0 +1,266 @@
+// Package provides isolation of panics raised by solvers
+package solver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// Panic of the solver recovered by the framework, wraps ErrSolverPanic
+// Part is 0 when the solver panicked outside of solving a part
+type PanicError struct {
+	Day   string
+	Op    string
+	Part  int
+	Value any
+	Stack []byte
+}
+
+// Returns e.g. d6 solve part 2: panic: index out of range: solver panic
+func (e *PanicError) Error() string {
+	if e.Part > 0 {
+		return fmt.Sprintf("%s %s part %d: panic: %v: %v", e.Day, e.Op, e.Part, e.Value, ErrSolverPanic)
+	}
+
+	return fmt.Sprintf("%s %s: panic: %v: %v", e.Day, e.Op, e.Value, ErrSolverPanic)
+}
+
+// Unwraps to ErrSolverPanic
+func (e *PanicError) Unwrap() error {
+	return ErrSolverPanic
+}
+
+// Callback receiving every recovered panic, e.g. to count them
+var panicHook atomic.Pointer[func(*PanicError)]
+
+// Sets callback receiving every recovered panic, has to be safe for
+// concurrent use, nil removes the callback
+func OnPanic(fn func(*PanicError)) {
+	if fn == nil {
+		panicHook.Store(nil)
+		return
+	}
+
+	panicHook.Store(&fn)
+}
+
+// Converts panic into PanicError stored in err
+// Has to be deferred directly by the guarded call
+func recoverPanic(day, op string, part int, err *error) {
+	r := recover()
+
+	if r == nil {
+		return
+	}
+
+	p := &PanicError{Day: day, Op: op, Part: part, Value: r, Stack: debug.Stack()}
+
+	if fn := panicHook.Load(); fn != nil {
+		(*fn)(p)
+	}
+
+	*err = p
+}
+
+// Wraps the solver, panics of the calls are returned as PanicError
+// Calls with context are supervised, they return on deadline even
+// if the solver ignores ctx, parts are solved in a child process if
+// the solver is isolated
+// Panics of goroutines started by the solver can't be recovered
+func guard(day string, s PuzzleSolverWithCtx) PuzzleSolverWithCtx {
+	g := guarded{day: day, s: s, run: isolatedRunner(day, s)}
+
+	if st, ok := s.(StepperWithCtx); ok {
+		return &guardedStepper{g, st}
+	}
+
+	return &g
+}
+
+// Solver recovering panics of the wrapped solver
+// Provides structured results and variants of every solver, SolvePart
+// derives the value of solvers without them, variants of solvers
+// without them are unknown
+type guarded struct {
+	day string
+	s   PuzzleSolverWithCtx
+
+	// runner of the child process, parameters and state of the solver
+	// kept between the children
+	run      ExternalRunner
+	params   Params
+	isolated isolatedState
+}
+
+// Inspection of the input parsed by the guarded solver
+type inspector interface {
+	describe() (map[string]int64, error)
+	score() (float64, bool, error)
+}
+
+// Returns the solver wrapped by the adapters, they hide its optional
+// interfaces
+func (g *guarded) target() any {
+	if a, ok := g.s.(interface{ unwrap() PuzzleSolver }); ok {
+		return a.unwrap()
+	}
+
+	return g.s
+}
+
+// Initializes the wrapped solver
+func (g *guarded) Init(reader io.Reader) (err error) {
+	defer recoverPanic(g.day, "init", 0, &err)
+
+	if err := g.setParams(context.Background()); err != nil {
+		return err
+	}
+
+	if g.run != nil {
+		return g.initIsolated(context.Background(), reader)
+	}
+
+	return g.s.Init(reader)
+}
+
+// Solves the puzzle with the wrapped solver
+func (g *guarded) Solve(part int) (output string, err error) {
+	defer recoverPanic(g.day, "solve", part, &err)
+
+	if g.run != nil {
+		result, err := g.solveIsolated(context.Background(), part)
+		return result.Output, err
+	}
+
+	return g.s.Solve(part)
+}
+
+// Initializes the wrapped solver on a supervised worker
+func (g *guarded) InitCtx(ctx context.Context, reader io.Reader) error {
+	_, err := supervise(ctx, func() (_ struct{}, err error) {
+		defer recoverPanic(g.day, "init", 0, &err)
+
+		if err := g.setParams(ctx); err != nil {
+			return struct{}{}, err
+		}
+
+		if g.run != nil {
+			return struct{}{}, g.initIsolated(ctx, reader)
+		}
+
+		return struct{}{}, g.s.InitCtx(ctx, reader)
+	})
+
+	return err
+}
+
+// Solves the puzzle with the wrapped solver on a supervised worker
+func (g *guarded) SolveCtx(ctx context.Context, part int) (string, error) {
+	if g.run != nil {
+		result, err := g.solveIsolated(ctx, part)
+		return result.Output, err
+	}
+
+	return supervise(ctx, func() (output string, err error) {
+		defer recoverPanic(g.day, "solve", part, &err)
+
+		return g.s.SolveCtx(ctx, part)
+	})
+}
+
+// Solves the puzzle with the wrapped solver on a supervised worker,
+// structured results of solvers without them carry the output only
+func (g *guarded) SolveResult(ctx context.Context, part int) (Result, error) {
+	if g.run != nil {
+		return g.solveIsolated(ctx, part)
+	}
+
+	return supervise(ctx, func() (result Result, err error) {
+		defer recoverPanic(g.day, "solve", part, &err)
+
+		if r, ok := g.target().(Resulter); ok {
+			return r.SolveResult(ctx, part)
+		}
+
+		result.Output, err = g.s.SolveCtx(ctx, part)
+
+		return result, err
+	})
+}
+
+// Solves the part with the variant of the wrapped solver on a
+// supervised worker
+func (g *guarded) SolveVariant(ctx context.Context, part int, variant string) (string, error) {
+	if g.run != nil {
+		child := externalSolver{run: g.run, input: g.isolated.input, params: g.params.Strings()}
+		return child.SolveVariant(ctx, part, variant)
+	}
+
+	return supervise(ctx, func() (output string, err error) {
+		defer recoverPanic(g.day, "solve", part, &err)
+
+		v, ok := g.target().(Varianter)
+
+		if !ok {
+			return "", fmt.Errorf("%s part %d variant %s: %w", g.day, part, variant, ErrUnknownVariant)
+		}
+
+		return v.SolveVariant(ctx, part, variant)
+	})
+}
+
+// Returns facts of the parsed input, nil if the solver does not
+// describe the input
+func (g *guarded) describe() (map[string]int64, error) {
+	if g.run != nil {
+		return g.isolated.facts, nil
+	}
+
+	if d, ok := g.target().(Describer); ok {
+		return describe(g.day, d)
+	}
+
+	return nil, nil
+}
+
+// Returns score of the parsed input, ok is false if the solver does
+// not score the input
+func (g *guarded) score() (score float64, ok bool, err error) {
+	if g.run != nil {
+		if g.isolated.score == nil {
+			return 0, false, nil
+		}
+
+		return *g.isolated.score, true, nil
+	}
+
+	if s, ok := g.target().(Scorer); ok {
+		score, err = scoreInput(g.day, s)
+		return score, true, err
+	}
+
+	return 0, false, nil
+}
+
+// Stepwise solver recovering panics of the wrapped solver
+type guardedStepper struct {
+	guarded
+	stepper StepperWithCtx
+}
+
+// Makes next step with the wrapped solver on a supervised worker,
+// isolated solvers step in child processes
+func (g *guardedStepper) Next(ctx context.Context) (string, error) {
+	if g.run != nil {
+		return g.nextIsolated(ctx)
+	}
+
+	return supervise(ctx, func() (state string, err error) {
+		defer recoverPanic(g.day, "step", 0, &err)
+
+		return g.stepper.Next(ctx)
+	})
+}
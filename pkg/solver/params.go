@@ -0,0 +1,184 @@
+// Package provides parameters of the solvers
+package solver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Types of the parameters
+const (
+	ParamInt    = "int"
+	ParamString = "string"
+)
+
+// Parameter of the puzzle declared in the metadata
+// Default is in the same text form as the values, Min and Max bound
+// values of int parameters and lengths of string parameters,
+// Max 0 is unbounded
+type Param struct {
+	Name        string `json:"name" example:"blinks"`
+	Type        string `json:"type" enums:"int,string" example:"int"`
+	Description string `json:"description,omitempty" example:"Blinks of every part"`
+	Default     string `json:"default" example:"0"`
+	Min         int    `json:"min" example:"0"`
+	Max         int    `json:"max,omitempty" example:"1000"`
+} //@name Param
+
+// Values of the parameters by name, int or string by their type
+type Params map[string]any
+
+// Interface of Puzzle Solver accepting parameters declared in its metadata
+// SetParams is called before every Init with all parameters, those not
+// given by the caller have their defaults
+type Parameterised interface {
+	Documented
+	SetParams(params Params) error
+}
+
+// Key of the parameters in the context
+type paramsKey struct{}
+
+// Returns context carrying the parameters of the solver initialized
+// with it, parameters are parsed by Metadata.ParseParams
+func WithParams(ctx context.Context, params Params) context.Context {
+	return context.WithValue(ctx, paramsKey{}, params)
+}
+
+// Returns parameters carried by the context, nil if there are none
+func paramsOf(ctx context.Context) Params {
+	params, _ := ctx.Value(paramsKey{}).(Params)
+
+	return params
+}
+
+// Returns value of the int parameter, 0 if it is missing
+func (p Params) Int(name string) int {
+	v, _ := p[name].(int)
+
+	return v
+}
+
+// Returns value of the string parameter, empty if it is missing
+func (p Params) Text(name string) string {
+	v, _ := p[name].(string)
+
+	return v
+}
+
+// Returns values in the text form accepted by ParseParams
+func (p Params) Strings() map[string]string {
+	if len(p) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(p))
+
+	for name, v := range p {
+		values[name] = fmt.Sprint(v)
+	}
+
+	return values
+}
+
+// Parses the values of the parameters, missing parameters get defaults
+// Returns error wrapping ErrInvalidParam if the parameter is unknown,
+// its value is not of its type or out of its bounds
+func (m Metadata) ParseParams(values map[string]string) (Params, error) {
+	for name := range values {
+		if !slices.ContainsFunc(m.Params, func(p Param) bool { return p.Name == name }) {
+			return nil, fmt.Errorf("unknown parameter %s: %w", name, ErrInvalidParam)
+		}
+	}
+
+	params := make(Params, len(m.Params))
+
+	for _, p := range m.Params {
+		text, ok := values[p.Name]
+
+		if !ok {
+			text = p.Default
+		}
+
+		v, err := p.parse(text)
+
+		if err != nil {
+			return nil, err
+		}
+
+		params[p.Name] = v
+	}
+
+	return params, nil
+}
+
+// Returns defaults of all parameters
+func (m Metadata) DefaultParams() Params {
+	params, _ := m.ParseParams(nil)
+
+	return params
+}
+
+// Parses the value of the parameter and checks its bounds
+func (p Param) parse(text string) (any, error) {
+	switch p.Type {
+	case ParamInt:
+		v, err := strconv.Atoi(strings.TrimSpace(text))
+
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: not a number %q: %w", p.Name, text, ErrInvalidParam)
+		}
+
+		if v < p.Min || (p.Max != 0 && v > p.Max) {
+			return nil, fmt.Errorf("parameter %s: %d out of bounds %s: %w", p.Name, v, p.Bounds(), ErrInvalidParam)
+		}
+
+		return v, nil
+	case ParamString:
+		if len(text) < p.Min || (p.Max != 0 && len(text) > p.Max) {
+			return nil, fmt.Errorf("parameter %s: length %d out of bounds %s: %w", p.Name, len(text), p.Bounds(), ErrInvalidParam)
+		}
+
+		return text, nil
+	}
+
+	return nil, fmt.Errorf("parameter %s: unknown type %s: %w", p.Name, p.Type, ErrInvalidParam)
+}
+
+// Returns bounds of the parameter, e.g. 0..1000, 0.. if unbounded
+func (p Param) Bounds() string {
+	if p.Max == 0 {
+		return fmt.Sprintf("%d..", p.Min)
+	}
+
+	return fmt.Sprintf("%d..%d", p.Min, p.Max)
+}
+
+// Sets parameters carried by ctx or defaults on the wrapped solver and
+// keeps them for the child process of the isolated solver
+// Returns error wrapping ErrInvalidParam if the solver is not
+// Parameterised but parameters were given
+func (g *guarded) setParams(ctx context.Context) error {
+	params := paramsOf(ctx)
+
+	ps, ok := g.target().(Parameterised)
+
+	if !ok {
+		if len(params) > 0 {
+			return fmt.Errorf("%s has no parameters: %w", g.day, ErrInvalidParam)
+		}
+
+		return nil
+	}
+
+	if params == nil {
+		params = ps.Metadata().DefaultParams()
+	}
+
+	g.params = params
+
+	return ps.SetParams(params)
+}
@@ -0,0 +1,62 @@
+// Package provides progress reporting of long running solvers
+package solver
+
+import (
+	"context"
+)
+
+// Progress of solving, done out of total units of work
+// Part is filled in by the caller which knows the part being solved
+type Progress struct {
+	Part  int `json:"part,omitempty"`
+	Done  int `json:"done"`
+	Total int `json:"total"`
+} //@name Progress
+
+// Callback receiving the progress, has to be safe for concurrent use
+type ProgressFunc func(Progress)
+
+// Interface of Puzzle Solver reporting progress of SolveCtx
+// through ReportProgress
+type ProgressReporter interface {
+	PuzzleSolverWithCtx
+	ReportsProgress() bool
+}
+
+// Key of the progress callback in the context
+type progressKey struct{}
+
+// Returns context carrying the progress callback
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// Returns progress callback carried by the context, nil if there is none
+func progressFunc(ctx context.Context) ProgressFunc {
+	fn, _ := ctx.Value(progressKey{}).(ProgressFunc)
+
+	return fn
+}
+
+// Reports progress to the callback carried by the context
+// Does nothing if there is no callback
+func ReportProgress(ctx context.Context, done, total int) {
+	if fn := progressFunc(ctx); fn != nil {
+		fn(Progress{Done: done, Total: total})
+	}
+}
+
+// Returns context reporting progress of the part
+// Does nothing if there is no callback
+func withPart(ctx context.Context, part int) context.Context {
+	fn := progressFunc(ctx)
+
+	if fn == nil {
+		return ctx
+	}
+
+	return WithProgress(ctx, func(p Progress) {
+		p.Part = part
+		fn(p)
+	})
+}
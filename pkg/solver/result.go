@@ -0,0 +1,111 @@
+// Package provides structured results of solving
+package solver
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// Result of solving a part
+// Output is the answer as returned by Solve, Value is the typed answer,
+// one of int64, *big.Int or string
+// CPUTime and Allocated are reported by the solver or its child process,
+// otherwise ProcessWide is set and they are approximations used by the
+// whole process during the solve, concurrent solves are included, small
+// allocations are counted once the runtime takes a new span for them
+type Result struct {
+	Part        int
+	Variant     string
+	Output      string
+	Value       any
+	ParseTime   time.Duration
+	SolveTime   time.Duration
+	CPUTime     time.Duration
+	Allocated   uint64
+	ProcessWide bool
+	Stats       map[string]int64
+}
+
+// Interface of Puzzle Solver providing structured results
+// Value and Stats are taken from the solver, times are measured by
+// the caller, CPU time and allocated bytes unless set by the solver
+type Resulter interface {
+	PuzzleSolverWithCtx
+	SolveResult(ctx context.Context, part int) (Result, error)
+}
+
+// Solves the part and measures the solve time, CPU time and allocated bytes
+// Solvers without structured results get the value derived from the output
+func SolvePart(ctx context.Context, s PuzzleSolverWithCtx, part int) (Result, error) {
+	return measure(part, func() (Result, error) {
+		if r, ok := s.(Resulter); ok {
+			return r.SolveResult(ctx, part)
+		}
+
+		output, err := s.SolveCtx(ctx, part)
+
+		return Result{Output: output}, err
+	})
+}
+
+// Solves the part with solve, measures it and fills in the value
+func measure(part int, solve func() (Result, error)) (Result, error) {
+	start := time.Now()
+	before := readUsage()
+
+	result, err := solve()
+
+	if err != nil {
+		return Result{}, err
+	}
+
+	result.Part = part
+	result.SolveTime = time.Since(start)
+
+	// solved elsewhere, e.g. in a child process reporting its usage
+	if result.CPUTime == 0 && result.Allocated == 0 {
+		used := before.since()
+		result.CPUTime, result.Allocated = used.cpu, used.allocated
+		result.ProcessWide = true
+	}
+
+	if result.Value == nil {
+		result.Value = ParseValue(result.Output)
+	}
+
+	if result.Output == "" {
+		result.Output = fmt.Sprint(result.Value)
+	}
+
+	return result, nil
+}
+
+// Returns typed value of the output
+// Integers fitting int64 are int64, larger integers *big.Int,
+// anything else is kept as string
+func ParseValue(output string) any {
+	if v, err := strconv.ParseInt(output, 10, 64); err == nil {
+		return v
+	}
+
+	if v, ok := new(big.Int).SetString(output, 10); ok {
+		return v
+	}
+
+	return output
+}
+
+// Returns name of the value type, int, bigint or string
+func ValueType(value any) string {
+	switch value.(type) {
+	case int64:
+		return "int"
+	case *big.Int:
+		return "bigint"
+	default:
+		return "string"
+	}
+}
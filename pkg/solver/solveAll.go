@@ -0,0 +1,61 @@
+// Package provides solving of all parts of a day at once
+package solver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Result of solving one part
+type PartResult struct {
+	Result
+	Err error
+}
+
+// Initializes the solver once and solves all parts listed in its
+// metadata concurrently, all parts share the deadline of ctx
+// Progress reported to the callback carried by ctx is tagged with the part
+// Returns results in order of the parts in metadata, error if the
+// solver is unknown or the initialization failed
+func SolveAll(ctx context.Context, name string, reader io.Reader) ([]PartResult, error) {
+	item, ok := Lookup(name)
+
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, ErrUnknownSolver)
+	}
+
+	s := guard(name, WithCtx(item.Constructor()))
+
+	start := time.Now()
+
+	if err := s.InitCtx(ctx, reader); err != nil {
+		return nil, err
+	}
+
+	parseTime := time.Since(start)
+
+	results := make([]PartResult, len(item.Metadata.Parts))
+
+	var wg sync.WaitGroup
+
+	for i, p := range item.Metadata.Parts {
+		wg.Add(1)
+		go func(i, part int) {
+			defer wg.Done()
+
+			result, err := SolvePart(withPart(ctx, part), s, part)
+
+			result.Part = part
+			result.ParseTime = parseTime
+
+			results[i] = PartResult{Result: result, Err: err}
+		}(i, p.Part)
+	}
+
+	wg.Wait()
+
+	return results, nil
+}
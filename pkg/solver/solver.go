@@ -14,31 +14,88 @@ import (
 // Errors returned by the solver can be tested againts these errors
 // using errors.Is
 var (
-	ErrInvalidInput = errors.New("invalid input")
-	ErrTimeout      = errors.New("solver timeout")
-	ErrUnknownPart  = errors.New("unknown part")
+	ErrInvalidInput   = errors.New("invalid input")
+	ErrTimeout        = errors.New("solver timeout")
+	ErrUnknownPart    = errors.New("unknown part")
+	ErrNoMoreSteps    = errors.New("no more steps")
+	ErrUnknownSolver  = errors.New("unknown solver")
+	ErrSolverPanic    = errors.New("solver panic")
+	ErrOverloaded     = errors.New("solver overloaded")
+	ErrUnknownVariant = errors.New("unknown variant")
+	ErrInvalidParam   = errors.New("invalid parameter")
 )
 
 // Interface of Puzzle Solver
+// Solve must not change the state set up by Init, one initialised
+// solver can solve any part any number of times in any order,
+// including concurrently
 type PuzzleSolver interface {
 	Init(reader io.Reader) error
 	Solve(part int) (string, error)
 }
 
+// Optional interfaces implemented by a registered solver
+// Detected once during registration
+type Capabilities struct {
+	Ctx           bool `json:"ctx"`
+	Stepper       bool `json:"stepper"`
+	StepperCtx    bool `json:"stepperCtx"`
+	Documented    bool `json:"documented"`
+	Progress      bool `json:"progress"`
+	Resulter      bool `json:"resulter"`
+	Describer     bool `json:"describer"`
+	Scorer        bool `json:"scorer"`
+	Varianter     bool `json:"varianter"`
+	Parameterised bool `json:"parameterised"`
+}
+
+// Puzzle part supported by the solver
+// Variants name implementations of the part, the first is the default
+type Part struct {
+	Part          int      `json:"part" example:"1"`
+	Label         string   `json:"label" example:"Total distance"`
+	ExampleAnswer string   `json:"exampleAnswer,omitempty" example:"11"`
+	Variants      []string `json:"variants,omitempty" example:"sequential,parallel"`
+} //@name Part
+
+// Description of the puzzle and the solver
+// Params declare parameters accepted by Parameterised solvers
+type Metadata struct {
+	Title       string  `json:"title" example:"Historian Hysteria"`
+	Description string  `json:"description"`
+	Parts       []Part  `json:"parts"`
+	Params      []Param `json:"params,omitempty"`
+	InputFormat string  `json:"inputFormat"`
+	Example     string  `json:"example"`
+} //@name Metadata
+
+// Interface of Puzzle Solver describing the puzzle it solves
+type Documented interface {
+	PuzzleSolver
+	Metadata() Metadata
+}
+
 // Registered solver
 type RegistryItem struct {
-	Name        string
-	Next        bool
-	Constructor func() PuzzleSolver
+	Name         string
+	Next         bool
+	Progress     bool
+	Capabilities Capabilities
+	Metadata     Metadata
+	Constructor  func() PuzzleSolver
 }
 
 // Registered solver for export purposes
 type RegistryItemPublic struct {
-	Name string `json:"name"`
-	Next bool   `json:"next"`
+	Name     string `json:"name"`
+	Next     bool   `json:"next"`
+	Progress bool   `json:"progress"`
+	Metadata
 } //@name RegistryItem
 
 // Interface of Puzzle Solver supporting stepwise solving
+// Next returns serialised state after the step, ErrNoMoreSteps once
+// the solving is finished
 type Stepper interface {
 	PuzzleSolver
 	Next() (string, error)
@@ -54,6 +111,8 @@ var keys []string
 var mu sync.RWMutex
 
 // Registers a solver and check for supported interfaces
+// Accepts any PuzzleSolver, solvers without context support
+// are wrapped by NewWithCtx
 // Keeps the keys ordered
 func Register(name string, constructor func() PuzzleSolver) {
 	mu.Lock()
@@ -73,14 +132,23 @@ func Register(name string, constructor func() PuzzleSolver) {
 		ps = constructor()
 	}()
 
-	if _, ok := ps.(Stepper); ok {
-		item.Next = true
+	item.Capabilities = detectCapabilities(ps)
+	item.Next = item.Capabilities.Stepper || item.Capabilities.StepperCtx
+	item.Progress = item.Capabilities.Progress
+	item.Metadata = defaultMetadata(name)
+
+	if d, ok := ps.(Documented); ok {
+		item.Metadata = d.Metadata()
+	}
+
+	// re-registration replaces the solver, key is kept only once
+	if _, ok := registry[name]; !ok {
+		keys = append(keys, name)
 	}
 
 	registry[name] = item
 
 	// sort the keys
-	keys = append(keys, name)
 	slices.SortFunc(keys, cmpDays)
 }
 
@@ -93,21 +161,78 @@ func ListRegistryItems() []RegistryItemPublic {
 
 	for _, k := range keys {
 		v := registry[k]
-		items = append(items, RegistryItemPublic{Name: v.Name, Next: v.Next})
+		items = append(items, RegistryItemPublic{Name: v.Name, Next: v.Next, Progress: v.Progress, Metadata: v.Metadata})
 	}
 
 	return items
 }
 
+// Returns registered solver and its capabilities
+func Lookup(name string) (RegistryItem, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	item, ok := registry[name]
+
+	return item, ok
+}
+
 // Factory for solvers
 func New(name string) (PuzzleSolver, bool) {
-	solver, ok := registry[name]
+	item, ok := Lookup(name)
 
 	if !ok {
 		return nil, false
 	}
 
-	return solver.Constructor(), true
+	return item.Constructor(), true
+}
+
+// Checks which optional interfaces the solver implements
+func detectCapabilities(ps PuzzleSolver) Capabilities {
+	var c Capabilities
+
+	if ps == nil {
+		return c
+	}
+
+	_, c.Ctx = ps.(PuzzleSolverWithCtx)
+	_, c.Stepper = ps.(Stepper)
+	_, c.StepperCtx = ps.(StepperWithCtx)
+	_, c.Documented = ps.(Documented)
+	_, c.Resulter = ps.(Resulter)
+	_, c.Describer = ps.(Describer)
+	_, c.Scorer = ps.(Scorer)
+	_, c.Varianter = ps.(Varianter)
+	_, c.Parameterised = ps.(Parameterised)
+
+	if r, ok := ps.(ProgressReporter); ok {
+		c.Progress = r.ReportsProgress()
+	}
+
+	return c
+}
+
+// Metadata of solvers which do not describe themselves
+func defaultMetadata(name string) Metadata {
+	return Metadata{
+		Title: name,
+		Parts: []Part{
+			{Part: 1, Label: "Part 1"},
+			{Part: 2, Label: "Part 2"},
+		},
+	}
+}
+
+// Checks if the part is supported
+func (m Metadata) HasPart(part int) bool {
+	for _, p := range m.Parts {
+		if p.Part == part {
+			return true
+		}
+	}
+
+	return false
 }
 
 func cmpDays(this, other string) int {
@@ -118,7 +243,7 @@ func cmpDays(this, other string) int {
 	o, _ := strconv.Atoi(oStr)
 
 	if t == o {
-		return 0
+		return strings.Compare(this, other)
 	} else if t > o {
 		return 1
 	}
@@ -4,8 +4,6 @@ package solver
 import (
 	"context"
 	"io"
-	"slices"
-	"sync"
 )
 
 // Interface of Puzzle Solver with context support
@@ -15,81 +13,155 @@ type PuzzleSolverWithCtx interface {
 	SolveCtx(ctx context.Context, part int) (string, error)
 }
 
-// Registered solver with context support
-type RegistryItemWithCtx struct {
-	Name        string
-	Next        bool
-	Constructor func() PuzzleSolverWithCtx
-}
-
 // Interface of Puzzle Solver with support for context and stepwise solving
 type StepperWithCtx interface {
 	PuzzleSolverWithCtx
 	Next(ctx context.Context) (string, error)
 }
 
-// Registry of solvers with context support
-var registryCtx = map[string]RegistryItemWithCtx{}
+// Registers a solver with context support
+// Kept for compatibility, the registry is shared with Register
+func RegisterWithCtx(name string, constructor func() PuzzleSolverWithCtx) {
+	Register(name, func() PuzzleSolver {
+		return constructor()
+	})
+}
 
-// Keys of the solvers with context support, sorted
-var keysCtx []string
+// Lists registered keys of solvers with context support
+// Every registered solver is available with context support
+func ListRegistryItemsWithCtx() []RegistryItemPublic {
+	return ListRegistryItems()
+}
 
-// Mutex guarding access to registry of solvers with context support
-var muCtx sync.RWMutex
+// Factory for solvers with context
+// Solvers without context support are wrapped in an adapter,
+// panics of the solver are returned as PanicError
+func NewWithCtx(name string) (PuzzleSolverWithCtx, bool) {
+	ps, ok := New(name)
 
-// Registers a solver and check for supported interfaces
-// Keeps the keys ordered
-func RegisterWithCtx(name string, constructor func() PuzzleSolverWithCtx) {
-	muCtx.Lock()
-	defer muCtx.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	return guard(name, WithCtx(ps)), true
+}
 
-	item := RegistryItemWithCtx{Name: name, Constructor: constructor}
+// Wraps solver into context aware adapter
+// Solvers already supporting context are returned as they are, unless
+// they step without context
+func WithCtx(ps PuzzleSolver) PuzzleSolverWithCtx {
+	switch s := ps.(type) {
+	case StepperWithCtx:
+		return s
+	case PuzzleSolverWithCtx:
+		if st, ok := s.(Stepper); ok {
+			return &ctxStepperAdapter{s, st}
+		}
+
+		return s
+	case Stepper:
+		return &stepperCtxAdapter{ctxAdapter{s}, s}
+	default:
+		return &ctxAdapter{s}
+	}
+}
 
-	var ps PuzzleSolverWithCtx
+// Initializes a solver with init reading the input from reader
+// Reading fails once ctx is done so parsing stops early, the deadline
+// is returned as ErrTimeout instead of the parse error it causes
+func InitWithCtx(ctx context.Context, reader io.Reader, init func(io.Reader) error) error {
+	if ctx.Err() != nil {
+		return ErrTimeout
+	}
 
-	// recovers from panic
-	func() {
-		defer func() {
-			if r := recover(); r != nil {
-				ps = nil
-			}
-		}()
-		ps = constructor()
-	}()
+	err := init(&ctxReader{ctx, reader})
 
-	if _, ok := ps.(Stepper); ok {
-		item.Next = true
+	if ctx.Err() != nil {
+		return ErrTimeout
 	}
 
-	registryCtx[name] = item
+	return err
+}
 
-	// sort the keys
-	keysCtx = append(keysCtx, name)
-	slices.SortFunc(keysCtx, cmpDays)
+// Reader failing with ErrTimeout once ctx is done
+type ctxReader struct {
+	ctx    context.Context
+	reader io.Reader
 }
 
-// Lists registered keys of solvers with context support
-func ListRegistryItemsWithCtx() []RegistryItemPublic {
-	muCtx.RLock()
-	defer muCtx.RUnlock()
+// Reads from the wrapped reader unless ctx is done
+func (r *ctxReader) Read(b []byte) (int, error) {
+	if r.ctx.Err() != nil {
+		return 0, ErrTimeout
+	}
 
-	items := make([]RegistryItemPublic, 0, len(registryCtx))
+	return r.reader.Read(b)
+}
 
-	for _, k := range keysCtx {
-		v := registryCtx[k]
-		items = append(items, RegistryItemPublic{Name: v.Name, Next: v.Next})
+// Adapter providing context support to plain solvers
+// Context is checked before and after the call, the call itself
+// can't be interrupted
+type ctxAdapter struct {
+	PuzzleSolver
+}
+
+// Initializes the wrapped solver
+func (a *ctxAdapter) InitCtx(ctx context.Context, reader io.Reader) error {
+	return InitWithCtx(ctx, reader, a.PuzzleSolver.Init)
+}
+
+// Returns the wrapped solver, e.g. to set its parameters
+func (a *ctxAdapter) unwrap() PuzzleSolver {
+	return a.PuzzleSolver
+}
+
+// Solves the puzzle with the wrapped solver
+func (a *ctxAdapter) SolveCtx(ctx context.Context, part int) (string, error) {
+	if ctx.Err() != nil {
+		return "", ErrTimeout
 	}
 
-	return items
+	result, err := a.PuzzleSolver.Solve(part)
+
+	if ctx.Err() != nil {
+		return "", ErrTimeout
+	}
+
+	return result, err
 }
 
-// Factory for solvers with context
-func NewWithCtx(name string) (PuzzleSolverWithCtx, bool) {
-	solver, ok := registryCtx[name]
+// Adapter providing context support to plain stepwise solvers
+type stepperCtxAdapter struct {
+	ctxAdapter
+	stepper Stepper
+}
 
-	if !ok {
-		return nil, false
+// Makes next step with the wrapped solver
+func (a *stepperCtxAdapter) Next(ctx context.Context) (string, error) {
+	if ctx.Err() != nil {
+		return "", ErrTimeout
+	}
+
+	return a.stepper.Next()
+}
+
+// Adapter providing stepwise solving with context to solvers supporting
+// context which step without it
+type ctxStepperAdapter struct {
+	PuzzleSolverWithCtx
+	stepper Stepper
+}
+
+// Returns the wrapped solver, e.g. to get its structured results
+func (a *ctxStepperAdapter) unwrap() PuzzleSolver {
+	return a.PuzzleSolverWithCtx
+}
+
+// Makes next step with the wrapped solver
+func (a *ctxStepperAdapter) Next(ctx context.Context) (string, error) {
+	if ctx.Err() != nil {
+		return "", ErrTimeout
 	}
 
-	return solver.Constructor(), true
+	return a.stepper.Next()
 }
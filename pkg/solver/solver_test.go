@@ -0,0 +1,1021 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Solver without context support
+type plainSolver struct {
+	input string
+}
+
+func (p *plainSolver) Init(reader io.Reader) error {
+	b, err := io.ReadAll(reader)
+	p.input = string(b)
+	return err
+}
+
+func (p *plainSolver) Solve(part int) (string, error) {
+	if part != 1 {
+		return "", ErrUnknownPart
+	}
+	return p.input, nil
+}
+
+// Solver without context support, supporting stepwise solving
+type plainStepper struct {
+	plainSolver
+	steps int
+}
+
+func (p *plainStepper) Next() (string, error) {
+	p.steps++
+	return strings.Repeat(".", p.steps), nil
+}
+
+// Solver with context support
+type ctxSolver struct {
+	plainSolver
+}
+
+func (p *ctxSolver) InitCtx(ctx context.Context, reader io.Reader) error {
+	return p.Init(reader)
+}
+
+func (p *ctxSolver) SolveCtx(ctx context.Context, part int) (string, error) {
+	return p.Solve(part)
+}
+
+func TestCapabilities(t *testing.T) {
+	Register("test-plain", func() PuzzleSolver { return &plainSolver{} })
+	Register("test-stepper", func() PuzzleSolver { return &plainStepper{} })
+	RegisterWithCtx("test-ctx", func() PuzzleSolverWithCtx { return &ctxSolver{} })
+
+	cases := []struct {
+		name string
+		want Capabilities
+		next bool
+	}{
+		{"test-plain", Capabilities{}, false},
+		{"test-stepper", Capabilities{Stepper: true}, true},
+		{"test-ctx", Capabilities{Ctx: true}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			item, ok := Lookup(c.name)
+
+			if !ok {
+				t.Fatalf("solver %s not registered", c.name)
+			}
+
+			if item.Capabilities != c.want {
+				t.Errorf("got %+v expected %+v", item.Capabilities, c.want)
+			}
+
+			if item.Next != c.next {
+				t.Errorf("next: got %t expected %t", item.Next, c.next)
+			}
+		})
+	}
+}
+
+// Solver describing itself
+type documentedSolver struct {
+	plainSolver
+}
+
+func (p *documentedSolver) Metadata() Metadata {
+	return Metadata{Title: "Documented", Parts: []Part{{Part: 1, Label: "Only part"}}}
+}
+
+func TestMetadata(t *testing.T) {
+	Register("test-plain", func() PuzzleSolver { return &plainSolver{} })
+	Register("test-documented", func() PuzzleSolver { return &documentedSolver{} })
+
+	cases := []struct {
+		name  string
+		title string
+		parts []int
+	}{
+		{"test-plain", "test-plain", []int{1, 2}},
+		{"test-documented", "Documented", []int{1}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			item, _ := Lookup(c.name)
+
+			if item.Metadata.Title != c.title {
+				t.Errorf("got title %s expected %s", item.Metadata.Title, c.title)
+			}
+
+			if len(item.Metadata.Parts) != len(c.parts) {
+				t.Fatalf("got %d parts expected %d", len(item.Metadata.Parts), len(c.parts))
+			}
+
+			for _, part := range c.parts {
+				if !item.Metadata.HasPart(part) {
+					t.Errorf("part %d missing", part)
+				}
+			}
+		})
+	}
+}
+
+func TestConsistentListing(t *testing.T) {
+	Register("test-plain", func() PuzzleSolver { return &plainSolver{} })
+	RegisterWithCtx("test-ctx", func() PuzzleSolverWithCtx { return &ctxSolver{} })
+
+	plain := ListRegistryItems()
+	withCtx := ListRegistryItemsWithCtx()
+
+	if len(plain) != len(withCtx) {
+		t.Fatalf("got %d and %d items", len(plain), len(withCtx))
+	}
+
+	for i := range plain {
+		if !reflect.DeepEqual(plain[i], withCtx[i]) {
+			t.Errorf("item %d: got %v and %v", i, plain[i], withCtx[i])
+		}
+
+		if _, ok := New(plain[i].Name); !ok {
+			t.Errorf("New: %s not found", plain[i].Name)
+		}
+
+		if _, ok := NewWithCtx(plain[i].Name); !ok {
+			t.Errorf("NewWithCtx: %s not found", plain[i].Name)
+		}
+	}
+}
+
+func TestReRegistration(t *testing.T) {
+	Register("test-twice", func() PuzzleSolver { return &plainSolver{} })
+	Register("test-twice", func() PuzzleSolver { return &plainStepper{} })
+
+	count := 0
+	for _, item := range ListRegistryItems() {
+		if item.Name == "test-twice" {
+			count++
+		}
+	}
+
+	if count != 1 {
+		t.Errorf("got %d items expected 1", count)
+	}
+}
+
+func TestCtxAdapter(t *testing.T) {
+	Register("test-plain", func() PuzzleSolver { return &plainSolver{} })
+	Register("test-stepper", func() PuzzleSolver { return &plainStepper{} })
+
+	t.Run("solve", func(t *testing.T) {
+		s, _ := NewWithCtx("test-plain")
+
+		_ = s.InitCtx(context.Background(), strings.NewReader("input"))
+		got, err := s.SolveCtx(context.Background(), 1)
+
+		if err != nil || got != "input" {
+			t.Errorf("got %s, %v expected input", got, err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		s, _ := NewWithCtx("test-plain")
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		<-ctx.Done()
+
+		if err := s.InitCtx(ctx, strings.NewReader("input")); !errors.Is(err, ErrTimeout) {
+			t.Errorf("got %v expected %v", err, ErrTimeout)
+		}
+
+		if _, err := s.SolveCtx(ctx, 1); !errors.Is(err, ErrTimeout) {
+			t.Errorf("got %v expected %v", err, ErrTimeout)
+		}
+	})
+
+	t.Run("stepper", func(t *testing.T) {
+		s, _ := NewWithCtx("test-stepper")
+
+		stepper, ok := s.(StepperWithCtx)
+
+		if !ok {
+			t.Fatalf("adapter does not implement StepperWithCtx")
+		}
+
+		got, _ := stepper.Next(context.Background())
+
+		if got != "." {
+			t.Errorf("got %s expected .", got)
+		}
+	})
+
+	t.Run("stepper with context support", func(t *testing.T) {
+		Register("test-ctx-stepper", func() PuzzleSolver { return &ctxStepper{} })
+
+		s, _ := NewWithCtx("test-ctx-stepper")
+
+		stepper, ok := s.(StepperWithCtx)
+
+		if !ok {
+			t.Fatalf("solver does not implement StepperWithCtx")
+		}
+
+		got, _ := stepper.Next(context.Background())
+
+		if got != "." {
+			t.Errorf("got %s expected .", got)
+		}
+
+		_ = s.InitCtx(context.Background(), strings.NewReader("input"))
+		result, err := SolvePart(context.Background(), s, 1)
+
+		if err != nil || result.Stats["calls"] != 1 {
+			t.Errorf("got %+v, %v expected structured result", result, err)
+		}
+	})
+}
+
+// Solver with context support and structured results, stepping without
+// context
+type ctxStepper struct {
+	resulterSolver
+	steps int
+}
+
+func (p *ctxStepper) Next() (string, error) {
+	p.steps++
+	return strings.Repeat(".", p.steps), nil
+}
+
+func TestSolveAll(t *testing.T) {
+	Register("test-plain", func() PuzzleSolver { return &plainSolver{} })
+
+	t.Run("parts", func(t *testing.T) {
+		results, err := SolveAll(context.Background(), "test-plain", strings.NewReader("input"))
+
+		if err != nil {
+			t.Fatalf("got %v expected nil", err)
+		}
+
+		if len(results) != 2 {
+			t.Fatalf("got %d results expected 2", len(results))
+		}
+
+		if results[0].Part != 1 || results[0].Output != "input" || results[0].Err != nil {
+			t.Errorf("part 1: got %+v", results[0])
+		}
+
+		if results[1].Part != 2 || !errors.Is(results[1].Err, ErrUnknownPart) {
+			t.Errorf("part 2: got %+v", results[1])
+		}
+	})
+
+	t.Run("unknown solver", func(t *testing.T) {
+		_, err := SolveAll(context.Background(), "test-missing", strings.NewReader("input"))
+
+		if !errors.Is(err, ErrUnknownSolver) {
+			t.Errorf("got %v expected %v", err, ErrUnknownSolver)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := SolveAll(ctx, "test-plain", strings.NewReader("input"))
+
+		if !errors.Is(err, ErrTimeout) {
+			t.Errorf("got %v expected %v", err, ErrTimeout)
+		}
+	})
+}
+
+// Solver with context support reporting progress
+type progressSolver struct {
+	ctxSolver
+}
+
+func (p *progressSolver) ReportsProgress() bool {
+	return true
+}
+
+func (p *progressSolver) SolveCtx(ctx context.Context, part int) (string, error) {
+	for i := 1; i <= 3; i++ {
+		ReportProgress(ctx, i, 3)
+	}
+	return strconv.Itoa(part), nil
+}
+
+func TestProgress(t *testing.T) {
+	Register("test-progress", func() PuzzleSolver { return &progressSolver{} })
+
+	t.Run("capability", func(t *testing.T) {
+		item, _ := Lookup("test-progress")
+
+		if !item.Progress || !item.Capabilities.Progress {
+			t.Errorf("progress capability not detected")
+		}
+	})
+
+	t.Run("no callback", func(t *testing.T) {
+		ReportProgress(context.Background(), 1, 1)
+	})
+
+	t.Run("solve all", func(t *testing.T) {
+		var mu sync.Mutex
+		reported := map[int][]Progress{}
+
+		ctx := WithProgress(context.Background(), func(p Progress) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported[p.Part] = append(reported[p.Part], p)
+		})
+
+		_, err := SolveAll(ctx, "test-progress", strings.NewReader("input"))
+
+		if err != nil {
+			t.Fatalf("got %v expected nil", err)
+		}
+
+		for _, part := range []int{1, 2} {
+			got := reported[part]
+
+			if len(got) != 3 || got[2] != (Progress{Part: part, Done: 3, Total: 3}) {
+				t.Errorf("part %d: got %+v", part, got)
+			}
+		}
+	})
+}
+
+// Solver with context support providing structured results
+type resulterSolver struct {
+	ctxSolver
+}
+
+func (p *resulterSolver) SolveResult(ctx context.Context, part int) (Result, error) {
+	return Result{Value: int64(part), Stats: map[string]int64{"calls": 1}}, nil
+}
+
+func TestSolvePart(t *testing.T) {
+	cases := []struct {
+		name       string
+		solver     PuzzleSolverWithCtx
+		input      string
+		wantOutput string
+		wantType   string
+		wantStats  bool
+	}{
+		{"int", &ctxSolver{}, "123", "123", "int", false},
+		{"big int", &ctxSolver{}, "123456789012345678901234567890", "123456789012345678901234567890", "bigint", false},
+		{"string", &ctxSolver{}, "abc", "abc", "string", false},
+		{"resulter", &resulterSolver{}, "", "1", "int", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_ = c.solver.InitCtx(context.Background(), strings.NewReader(c.input))
+
+			got, err := SolvePart(context.Background(), c.solver, 1)
+
+			if err != nil {
+				t.Fatalf("got %v expected nil", err)
+			}
+
+			if got.Part != 1 || got.Output != c.wantOutput || ValueType(got.Value) != c.wantType {
+				t.Errorf("got %+v expected output %s of type %s", got, c.wantOutput, c.wantType)
+			}
+
+			if (got.Stats != nil) != c.wantStats {
+				t.Errorf("got stats %v", got.Stats)
+			}
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		s := &ctxSolver{}
+
+		if _, err := SolvePart(context.Background(), s, 2); !errors.Is(err, ErrUnknownPart) {
+			t.Errorf("got %v expected %v", err, ErrUnknownPart)
+		}
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		got, _ := SolvePart(context.Background(), &allocatingSolver{}, 1)
+
+		if got.Allocated < 1<<20 {
+			t.Errorf("got %d bytes allocated expected at least %d", got.Allocated, 1<<20)
+		}
+
+		if got.CPUTime < 0 || got.SolveTime <= 0 {
+			t.Errorf("got cpu time %v solve time %v", got.CPUTime, got.SolveTime)
+		}
+
+		if !got.ProcessWide {
+			t.Errorf("got usage of the solve expected process-wide approximation")
+		}
+	})
+
+	t.Run("usage reported by solver", func(t *testing.T) {
+		got, _ := SolvePart(context.Background(), &usageSolver{}, 1)
+
+		if got.CPUTime != time.Second || got.Allocated != 42 {
+			t.Errorf("got %v, %d expected reported 1s, 42", got.CPUTime, got.Allocated)
+		}
+
+		if got.ProcessWide {
+			t.Errorf("got process-wide approximation expected usage reported by solver")
+		}
+	})
+}
+
+// Solver allocating a MiB on every solve
+type allocatingSolver struct {
+	ctxSolver
+}
+
+var allocated []byte
+
+func (p *allocatingSolver) SolveCtx(ctx context.Context, part int) (string, error) {
+	allocated = make([]byte, 1<<20)
+	return strconv.Itoa(len(allocated)), nil
+}
+
+// Solver reporting usage of solving elsewhere
+type usageSolver struct {
+	ctxSolver
+}
+
+func (p *usageSolver) SolveResult(ctx context.Context, part int) (Result, error) {
+	return Result{Output: "1", CPUTime: time.Second, Allocated: 42}, nil
+}
+
+// Solver without context support describing the input
+type describerSolver struct {
+	plainSolver
+}
+
+func (p *describerSolver) Init(reader io.Reader) error {
+	if err := p.plainSolver.Init(reader); err != nil {
+		return err
+	}
+
+	if p.input == "" {
+		return ErrInvalidInput
+	}
+
+	return nil
+}
+
+func (p *describerSolver) Describe() map[string]int64 {
+	return map[string]int64{"length": int64(len(p.input))}
+}
+
+func TestValidate(t *testing.T) {
+	Register("test-describer", func() PuzzleSolver { return &describerSolver{} })
+
+	if item, _ := Lookup("test-describer"); !item.Capabilities.Describer {
+		t.Errorf("got %+v expected describer", item.Capabilities)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		v, err := Validate(context.Background(), "test-describer", strings.NewReader("input"))
+
+		if err != nil || !v.Valid() || v.Facts["length"] != 5 {
+			t.Errorf("got %+v, %v expected valid with length 5", v, err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		v, err := Validate(context.Background(), "test-describer", strings.NewReader(""))
+
+		if err != nil || v.Valid() || v.Facts != nil {
+			t.Errorf("got %+v, %v expected invalid", v, err)
+		}
+	})
+
+	t.Run("unknown solver", func(t *testing.T) {
+		_, err := Validate(context.Background(), "test-missing", strings.NewReader("input"))
+
+		if !errors.Is(err, ErrUnknownSolver) {
+			t.Errorf("got %v expected %v", err, ErrUnknownSolver)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := Validate(ctx, "test-describer", strings.NewReader("input"))
+
+		if !errors.Is(err, ErrTimeout) {
+			t.Errorf("got %v expected %v", err, ErrTimeout)
+		}
+	})
+}
+
+// Solver without context support scoring the input
+type scorerSolver struct {
+	describerSolver
+}
+
+func (p *scorerSolver) Score() float64 {
+	switch p.input {
+	case "scored":
+		return 2
+	case "unlikely":
+		return 0
+	}
+
+	return 0.5
+}
+
+func TestDetect(t *testing.T) {
+	Register("test-plain", func() PuzzleSolver { return &plainSolver{} })
+	Register("test-scorer", func() PuzzleSolver { return &scorerSolver{} })
+
+	t.Run("ranked", func(t *testing.T) {
+		got, err := Detect(context.Background(), strings.NewReader("scored"), time.Second)
+
+		if err != nil || len(got) == 0 {
+			t.Fatalf("got %v, %v expected candidates", got, err)
+		}
+
+		if got[0] != (Candidate{"test-scorer", 1}) {
+			t.Errorf("got %+v expected test-scorer with score 1 first", got[0])
+		}
+
+		if !slices.Contains(got, Candidate{"test-plain", defaultScore}) {
+			t.Errorf("got %+v expected test-plain with default score", got)
+		}
+	})
+
+	t.Run("zero score skipped", func(t *testing.T) {
+		got, _ := Detect(context.Background(), strings.NewReader("unlikely"), time.Second)
+
+		for _, c := range got {
+			if c.Name == "test-scorer" {
+				t.Errorf("got %+v expected test-scorer skipped", got)
+			}
+		}
+
+		if !slices.Contains(got, Candidate{"test-plain", defaultScore}) {
+			t.Errorf("got %+v expected test-plain with default score", got)
+		}
+	})
+
+	t.Run("invalid input skipped", func(t *testing.T) {
+		got, _ := Detect(context.Background(), strings.NewReader(""), time.Second)
+
+		for _, c := range got {
+			if c.Name == "test-scorer" {
+				t.Errorf("got %+v expected test-scorer skipped", got)
+			}
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := Detect(ctx, strings.NewReader("scored"), time.Second); !errors.Is(err, ErrTimeout) {
+			t.Errorf("got %v expected %v", err, ErrTimeout)
+		}
+	})
+}
+
+// Solver without context support panicking on the input
+type panickingSolver struct {
+	plainSolver
+}
+
+func (p *panickingSolver) Init(reader io.Reader) error {
+	if err := p.plainSolver.Init(reader); err != nil {
+		return err
+	}
+
+	if p.input == "init" {
+		panic("init")
+	}
+
+	return nil
+}
+
+func (p *panickingSolver) Solve(part int) (string, error) {
+	var field [][]byte
+	return string(field[part][0]), nil
+}
+
+func (p *panickingSolver) Next() (string, error) {
+	panic("next")
+}
+
+func TestPanic(t *testing.T) {
+	Register("test-panicking", func() PuzzleSolver { return &panickingSolver{} })
+
+	var panics atomic.Int32
+	OnPanic(func(p *PanicError) { panics.Add(1) })
+	defer OnPanic(nil)
+
+	cases := []struct {
+		name  string
+		call  func() error
+		op    string
+		part  int
+	}{
+		{"init", func() error {
+			s, _ := NewWithCtx("test-panicking")
+			return s.InitCtx(context.Background(), strings.NewReader("init"))
+		}, "init", 0},
+		{"solve", func() error {
+			s, _ := NewWithCtx("test-panicking")
+			_ = s.InitCtx(context.Background(), strings.NewReader("input"))
+			_, err := SolvePart(context.Background(), s, 2)
+			return err
+		}, "solve", 2},
+		{"next", func() error {
+			s, _ := NewWithCtx("test-panicking")
+			_, err := s.(StepperWithCtx).Next(context.Background())
+			return err
+		}, "step", 0},
+		{"solve all", func() error {
+			results, _ := SolveAll(context.Background(), "test-panicking", strings.NewReader("input"))
+			return results[0].Err
+		}, "solve", 1},
+		{"validate", func() error {
+			_, err := Validate(context.Background(), "test-panicking", strings.NewReader("init"))
+			return err
+		}, "init", 0},
+	}
+
+	for i, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.call()
+
+			var pe *PanicError
+
+			if !errors.As(err, &pe) || !errors.Is(err, ErrSolverPanic) {
+				t.Fatalf("got %v expected %v", err, ErrSolverPanic)
+			}
+
+			if pe.Day != "test-panicking" || pe.Op != c.op || pe.Part != c.part || len(pe.Stack) == 0 {
+				t.Errorf("got %s %s part %d expected %s part %d", pe.Day, pe.Op, pe.Part, c.op, c.part)
+			}
+
+			if got := panics.Load(); got < int32(i+1) {
+				t.Errorf("got %d panics counted expected at least %d", got, i+1)
+			}
+		})
+	}
+}
+
+// Solver ignoring context, solving blocks until released
+type blockingSolver struct {
+	release chan struct{}
+}
+
+func (p *blockingSolver) Init(reader io.Reader) error {
+	return nil
+}
+
+func (p *blockingSolver) Solve(part int) (string, error) {
+	<-p.release
+	return "done", nil
+}
+
+func TestSupervise(t *testing.T) {
+	release := make(chan struct{})
+	Register("test-blocking", func() PuzzleSolver { return &blockingSolver{release} })
+
+	before := Supervisor()
+
+	SetMaxAbandoned(1)
+	defer SetMaxAbandoned(int(before.MaxAbandoned))
+
+	solve := func() (time.Duration, error) {
+		s, _ := NewWithCtx("test-blocking")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := s.SolveCtx(ctx, 1)
+
+		return time.Since(start), err
+	}
+
+	if took, err := solve(); !errors.Is(err, ErrTimeout) || took > time.Second {
+		t.Fatalf("got %v after %v expected %v on time", err, took, ErrTimeout)
+	}
+
+	if got := Supervisor(); got.Abandoned != before.Abandoned+1 || got.AbandonedTotal != before.AbandonedTotal+1 {
+		t.Errorf("got %+v expected one more abandoned than %+v", got, before)
+	}
+
+	if _, err := solve(); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("got %v expected %v", err, ErrOverloaded)
+	}
+
+	if got := Supervisor(); got.Rejected != before.Rejected+1 {
+		t.Errorf("got %d rejected expected %d", got.Rejected, before.Rejected+1)
+	}
+
+	close(release)
+
+	for deadline := time.Now().Add(time.Second); Supervisor().Abandoned != before.Abandoned; {
+		if time.Now().After(deadline) {
+			t.Fatalf("abandoned solve not released, got %+v", Supervisor())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if took, err := solve(); err != nil || took > time.Second {
+		t.Errorf("got %v after %v expected no error", err, took)
+	}
+}
+
+// Reader blocking until the context is done
+type stallingReader struct {
+	ctx context.Context
+}
+
+func (r *stallingReader) Read(b []byte) (int, error) {
+	<-r.ctx.Done()
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestInitWithCtx(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := InitWithCtx(ctx, &stallingReader{ctx}, func(reader io.Reader) error {
+		_, err := io.ReadAll(reader)
+		return err
+	})
+
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("got %v expected %v", err, ErrTimeout)
+	}
+}
+
+// Solver with variants of part 1, broken one disagrees
+type variantSolver struct {
+	ctxSolver
+}
+
+func (p *variantSolver) Metadata() Metadata {
+	return Metadata{Title: "Variants", Parts: []Part{
+		{Part: 1, Label: "Input", Variants: []string{"plain", "copy", "broken"}},
+		{Part: 2, Label: "Unknown"},
+	}}
+}
+
+func (p *variantSolver) SolveVariant(ctx context.Context, part int, variant string) (string, error) {
+	switch variant {
+	case "plain":
+		return p.SolveCtx(ctx, part)
+	case "copy":
+		return strings.Clone(p.input), nil
+	case "broken":
+		return p.input + "!", nil
+	}
+
+	return "", fmt.Errorf("variant %s: %w", variant, ErrUnknownVariant)
+}
+
+// Solver with variants of part 1, first one fails
+type failingVariantSolver struct {
+	variantSolver
+}
+
+func (p *failingVariantSolver) Metadata() Metadata {
+	return Metadata{Title: "Failing variants", Parts: []Part{
+		{Part: 1, Label: "Input", Variants: []string{"failing", "plain", "copy", "broken"}},
+	}}
+}
+
+func (p *failingVariantSolver) SolveVariant(ctx context.Context, part int, variant string) (string, error) {
+	if variant == "failing" {
+		return "", errors.New("failing variant")
+	}
+
+	return p.variantSolver.SolveVariant(ctx, part, variant)
+}
+
+func TestVariants(t *testing.T) {
+	Register("test-variants", func() PuzzleSolver { return &variantSolver{} })
+
+	item, _ := Lookup("test-variants")
+
+	if !item.Capabilities.Varianter || !item.Metadata.HasVariant(1, "copy") || item.Metadata.HasVariant(2, "copy") {
+		t.Fatalf("got %+v", item)
+	}
+
+	t.Run("solve variant", func(t *testing.T) {
+		s, _ := NewWithCtx("test-variants")
+		_ = s.InitCtx(context.Background(), strings.NewReader("42"))
+
+		got, err := SolveVariant(context.Background(), s, 1, "copy")
+
+		if err != nil || got.Output != "42" || got.Variant != "copy" || got.Value != int64(42) {
+			t.Errorf("got %+v, %v expected 42 of variant copy", got, err)
+		}
+
+		if _, err := SolveVariant(context.Background(), s, 1, "unknown"); !errors.Is(err, ErrUnknownVariant) {
+			t.Errorf("got %v expected %v", err, ErrUnknownVariant)
+		}
+	})
+
+	t.Run("crosscheck", func(t *testing.T) {
+		c, err := CrosscheckPart(context.Background(), "test-variants", 1, strings.NewReader("42"))
+
+		if err != nil || len(c.Results) != 3 {
+			t.Fatalf("got %+v, %v expected 3 results", c, err)
+		}
+
+		if c.Agree() || !slices.Equal(c.Disagree, []string{"broken"}) {
+			t.Errorf("got disagreeing %v expected [broken]", c.Disagree)
+		}
+
+		if c.Results[2].Output != "42!" || c.Results[2].Variant != "broken" {
+			t.Errorf("got %+v expected output 42! of broken", c.Results[2])
+		}
+	})
+
+	t.Run("crosscheck with failing reference", func(t *testing.T) {
+		Register("test-variants-failing", func() PuzzleSolver { return &failingVariantSolver{} })
+
+		c, err := CrosscheckPart(context.Background(), "test-variants-failing", 1, strings.NewReader("42"))
+
+		if err != nil || len(c.Results) != 4 {
+			t.Fatalf("got %+v, %v expected 4 results", c, err)
+		}
+
+		if !slices.Equal(c.Failed, []string{"failing"}) || !slices.Equal(c.Disagree, []string{"broken"}) || c.Agree() {
+			t.Errorf("got failed %v disagreeing %v expected [failing] [broken]", c.Failed, c.Disagree)
+		}
+
+		if reference, ok := c.Reference(); !ok || reference.Variant != "plain" {
+			t.Errorf("got reference %+v expected plain", reference)
+		}
+	})
+
+	t.Run("crosscheck without variants", func(t *testing.T) {
+		if _, err := CrosscheckPart(context.Background(), "test-variants", 2, strings.NewReader("42")); !errors.Is(err, ErrUnknownVariant) {
+			t.Errorf("got %v expected %v", err, ErrUnknownVariant)
+		}
+	})
+}
+
+func TestIntegers(t *testing.T) {
+	t.Run("checked arithmetic", func(t *testing.T) {
+		cases := []struct {
+			name   string
+			fn     func(a, b int) (int, bool)
+			a, b   int
+			want   int
+			wantOk bool
+		}{
+			{"add", AddInt, 40, 2, 42, true},
+			{"add negative", AddInt, 40, -42, -2, true},
+			{"add overflow", AddInt, math.MaxInt, 1, 0, false},
+			{"add underflow", AddInt, math.MinInt, -1, 0, false},
+			{"mul", MulInt, 21, 2, 42, true},
+			{"mul zero", MulInt, math.MaxInt, 0, 0, true},
+			{"mul overflow", MulInt, 1 << 32, 1 << 31, 0, false},
+			{"mul min by -1", MulInt, math.MinInt, -1, 0, false},
+		}
+
+		for _, c := range cases {
+			got, ok := c.fn(c.a, c.b)
+
+			if ok != c.wantOk || (ok && got != c.want) {
+				t.Errorf("%s: got %d, %t expected %d, %t", c.name, got, ok, c.want, c.wantOk)
+			}
+		}
+	})
+
+	t.Run("sum", func(t *testing.T) {
+		var s Sum
+
+		s.Add(40)
+		s.Add(2)
+
+		if s.String() != "42" || s.Value() != int64(42) {
+			t.Errorf("got %s (%T) expected int 42", s.String(), s.Value())
+		}
+
+		s.Add(math.MaxInt)
+
+		if s.String() != "9223372036854775849" || ValueType(s.Value()) != "bigint" {
+			t.Errorf("got %s (%T) expected bigint 9223372036854775849", s.String(), s.Value())
+		}
+
+		s.Add(math.MinInt)
+
+		if s.String() != "41" || s.Value() != int64(41) || s.Big().Int64() != 41 {
+			t.Errorf("got %s (%T) expected int 41", s.String(), s.Value())
+		}
+	})
+}
+
+// Solver repeating the input, times and suffix are parameters
+type paramSolver struct {
+	ctxSolver
+	times  int
+	suffix string
+}
+
+func (p *paramSolver) Metadata() Metadata {
+	return Metadata{Title: "Params", Parts: []Part{{Part: 1, Label: "Repeated input"}}, Params: []Param{
+		{Name: "times", Type: ParamInt, Default: "1", Min: 1, Max: 3},
+		{Name: "suffix", Type: ParamString, Max: 2},
+	}}
+}
+
+func (p *paramSolver) SetParams(params Params) error {
+	p.times, p.suffix = params.Int("times"), params.Text("suffix")
+
+	return nil
+}
+
+func (p *paramSolver) SolveCtx(ctx context.Context, part int) (string, error) {
+	return strings.Repeat(p.input, p.times) + p.suffix, nil
+}
+
+func TestParams(t *testing.T) {
+	Register("test-params", func() PuzzleSolver { return &paramSolver{} })
+
+	item, _ := Lookup("test-params")
+
+	if !item.Capabilities.Parameterised {
+		t.Fatalf("got %+v expected parameterised", item.Capabilities)
+	}
+
+	t.Run("parse", func(t *testing.T) {
+		cases := []struct {
+			name    string
+			values  map[string]string
+			want    Params
+			wantErr error
+		}{
+			{"defaults", nil, Params{"times": 1, "suffix": ""}, nil},
+			{"values", map[string]string{"times": " 3", "suffix": "!"}, Params{"times": 3, "suffix": "!"}, nil},
+			{"unknown", map[string]string{"blinks": "3"}, nil, ErrInvalidParam},
+			{"not a number", map[string]string{"times": "x"}, nil, ErrInvalidParam},
+			{"out of bounds", map[string]string{"times": "4"}, nil, ErrInvalidParam},
+			{"too long", map[string]string{"suffix": "!!!"}, nil, ErrInvalidParam},
+		}
+
+		for _, c := range cases {
+			got, err := item.Metadata.ParseParams(c.values)
+
+			if !errors.Is(err, c.wantErr) || !reflect.DeepEqual(got, c.want) {
+				t.Errorf("%s: got %v, %v expected %v, %v", c.name, got, err, c.want, c.wantErr)
+			}
+		}
+	})
+
+	t.Run("set before init", func(t *testing.T) {
+		params, _ := item.Metadata.ParseParams(map[string]string{"times": "2", "suffix": "!"})
+
+		s, _ := NewWithCtx("test-params")
+		_ = s.InitCtx(WithParams(context.Background(), params), strings.NewReader("ab"))
+
+		if got, _ := s.SolveCtx(context.Background(), 1); got != "abab!" {
+			t.Errorf("got %s expected abab!", got)
+		}
+
+		// re-initialized without parameters gets defaults
+		_ = s.InitCtx(context.Background(), strings.NewReader("ab"))
+
+		if got, _ := s.SolveCtx(context.Background(), 1); got != "ab" {
+			t.Errorf("got %s expected ab", got)
+		}
+	})
+
+	t.Run("solver without parameters", func(t *testing.T) {
+		Register("test-no-params", func() PuzzleSolver { return &ctxSolver{} })
+
+		s, _ := NewWithCtx("test-no-params")
+		err := s.InitCtx(WithParams(context.Background(), Params{"times": 2}), strings.NewReader("ab"))
+
+		if !errors.Is(err, ErrInvalidParam) {
+			t.Errorf("got %v expected %v", err, ErrInvalidParam)
+		}
+	})
+}
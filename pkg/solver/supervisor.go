@@ -0,0 +1,107 @@
+// Package provides supervised execution of solver calls
+package solver
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+)
+
+// Counters of the supervisor
+type SupervisorStats struct {
+	// computations past their deadline which are still running
+	Abandoned int64 `json:"abandoned"`
+	// computations abandoned since the start
+	AbandonedTotal int64 `json:"abandonedTotal"`
+	// calls refused because too many computations were abandoned
+	Rejected int64 `json:"rejected"`
+	// limit of running abandoned computations, 0 is unlimited
+	MaxAbandoned int64 `json:"maxAbandoned"`
+}
+
+// State of the supervisor shared by all solvers
+var supervisor struct {
+	abandoned      atomic.Int64
+	abandonedTotal atomic.Int64
+	rejected       atomic.Int64
+	maxAbandoned   atomic.Int64
+}
+
+func init() {
+	supervisor.maxAbandoned.Store(int64(runtime.NumCPU()))
+}
+
+// Sets limit of abandoned computations which may still be running,
+// calls beyond the limit fail with ErrOverloaded, 0 is unlimited
+// Defaults to the number of CPUs
+func SetMaxAbandoned(n int) {
+	supervisor.maxAbandoned.Store(int64(max(n, 0)))
+}
+
+// Returns counters of the supervisor
+func Supervisor() SupervisorStats {
+	return SupervisorStats{
+		Abandoned:      supervisor.abandoned.Load(),
+		AbandonedTotal: supervisor.abandonedTotal.Load(),
+		Rejected:       supervisor.rejected.Load(),
+		MaxAbandoned:   supervisor.maxAbandoned.Load(),
+	}
+}
+
+// Runs fn on a worker and waits for it until ctx is done
+// Returns ErrTimeout on time even if fn ignores ctx, fn is abandoned
+// and keeps running until it returns, its result is dropped
+// Returns ErrOverloaded without running fn if too many abandoned
+// computations are still running
+// Without deadline fn is run directly
+func supervise[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	var zero T
+
+	if ctx.Done() == nil {
+		return fn()
+	}
+
+	if ctx.Err() != nil {
+		return zero, ErrTimeout
+	}
+
+	if limit := supervisor.maxAbandoned.Load(); limit > 0 && supervisor.abandoned.Load() >= limit {
+		supervisor.rejected.Add(1)
+		return zero, ErrOverloaded
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		value, err := fn()
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+	}
+
+	// finished together with the deadline
+	select {
+	case r := <-done:
+		return r.value, r.err
+	default:
+	}
+
+	supervisor.abandoned.Add(1)
+	supervisor.abandonedTotal.Add(1)
+
+	go func() {
+		<-done
+		supervisor.abandoned.Add(-1)
+	}()
+
+	return zero, ErrTimeout
+}
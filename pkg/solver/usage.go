@@ -0,0 +1,37 @@
+// Package provides accounting of resources used by solving
+package solver
+
+import (
+	"runtime/metrics"
+	"time"
+)
+
+// Cumulative bytes allocated on the heap by the process
+const allocsMetric = "/gc/heap/allocs:bytes"
+
+// Resources used by the process so far
+type usage struct {
+	cpu       time.Duration
+	allocated uint64
+}
+
+// Returns resources used by the process so far
+func readUsage() usage {
+	sample := []metrics.Sample{{Name: allocsMetric}}
+	metrics.Read(sample)
+
+	u := usage{cpu: cpuTime()}
+
+	if sample[0].Value.Kind() == metrics.KindUint64 {
+		u.allocated = sample[0].Value.Uint64()
+	}
+
+	return u
+}
+
+// Returns resources used since u was read
+func (u usage) since() usage {
+	now := readUsage()
+
+	return usage{cpu: now.cpu - u.cpu, allocated: now.allocated - u.allocated}
+}
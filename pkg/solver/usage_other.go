@@ -0,0 +1,10 @@
+//go:build !unix
+
+package solver
+
+import "time"
+
+// CPU time is not available, reported as zero
+func cpuTime() time.Duration {
+	return 0
+}
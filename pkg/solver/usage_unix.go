@@ -0,0 +1,19 @@
+//go:build unix
+
+package solver
+
+import (
+	"syscall"
+	"time"
+)
+
+// Returns user and system CPU time of the process
+func cpuTime() time.Duration {
+	var ru syscall.Rusage
+
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0
+	}
+
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+}
@@ -0,0 +1,81 @@
+// Package provides validation of the input without solving
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Interface of Puzzle Solver describing the parsed input
+// Describe is called after successful Init and returns summary facts
+// of the input, e.g. width and height of the grid
+type Describer interface {
+	PuzzleSolver
+	Describe() map[string]int64
+}
+
+// Result of the input validation
+// Err is the input error, nil for valid input
+// Facts are set for valid input of solvers implementing Describer
+type Validation struct {
+	ParseTime time.Duration
+	Facts     map[string]int64
+	Err       error
+}
+
+// Returns true if the input is valid
+func (v Validation) Valid() bool {
+	return v.Err == nil
+}
+
+// Initializes the solver without solving any part
+// Input errors are returned in Validation, error if the solver is
+// unknown or the initialization failed for other reason, e.g. timeout
+func Validate(ctx context.Context, name string, reader io.Reader) (Validation, error) {
+	item, ok := Lookup(name)
+
+	if !ok {
+		return Validation{}, fmt.Errorf("%s: %w", name, ErrUnknownSolver)
+	}
+
+	v, _, err := validate(ctx, item, reader)
+
+	return v, err
+}
+
+// Initializes new solver of the item, returns the solver for further
+// inspection of the parsed input
+func validate(ctx context.Context, item RegistryItem, reader io.Reader) (Validation, inspector, error) {
+	s := guard(item.Name, WithCtx(item.Constructor()))
+
+	start := time.Now()
+	err := s.InitCtx(ctx, reader)
+
+	v := Validation{ParseTime: time.Since(start)}
+
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		v.Err = err
+		return v, nil, nil
+	case err != nil:
+		return Validation{}, nil, err
+	}
+
+	in := s.(inspector)
+
+	if v.Facts, err = in.describe(); err != nil {
+		return Validation{}, nil, err
+	}
+
+	return v, in, nil
+}
+
+// Describes the input, panic is returned as PanicError
+func describe(day string, d Describer) (facts map[string]int64, err error) {
+	defer recoverPanic(day, "describe", 0, &err)
+
+	return d.Describe(), nil
+}
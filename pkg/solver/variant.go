@@ -0,0 +1,147 @@
+// Package provides named variants of the parts
+package solver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// Interface of Puzzle Solver with several implementations of a part
+// Variants of the part are listed in its metadata, the first one is
+// the implementation used by Solve and SolveCtx
+type Varianter interface {
+	PuzzleSolverWithCtx
+	SolveVariant(ctx context.Context, part int, variant string) (string, error)
+}
+
+// Result of one variant in the cross-check
+type VariantResult struct {
+	Result
+	Err error
+}
+
+// Results of all variants of the part solved on the same input
+// Disagree lists variants which returned other output than the first
+// successful variant, Failed lists variants which returned error
+type Crosscheck struct {
+	Part     int
+	Results  []VariantResult
+	Disagree []string
+	Failed   []string
+}
+
+// Returns names of the variants of the part, nil if the part has only
+// one implementation
+func (m Metadata) Variants(part int) []string {
+	for _, p := range m.Parts {
+		if p.Part == part {
+			return p.Variants
+		}
+	}
+
+	return nil
+}
+
+// Checks if the part has the variant, empty variant is the default
+func (m Metadata) HasVariant(part int, variant string) bool {
+	if variant == "" {
+		return m.HasPart(part)
+	}
+
+	return slices.Contains(m.Variants(part), variant)
+}
+
+// Solves the part with the variant like SolvePart
+// Empty variant solves with the default implementation
+func SolveVariant(ctx context.Context, s PuzzleSolverWithCtx, part int, variant string) (Result, error) {
+	if variant == "" {
+		return SolvePart(ctx, s, part)
+	}
+
+	v, ok := s.(Varianter)
+
+	if !ok {
+		return Result{}, fmt.Errorf("part %d variant %s: %w", part, variant, ErrUnknownVariant)
+	}
+
+	result, err := measure(part, func() (Result, error) {
+		output, err := v.SolveVariant(ctx, part, variant)
+		return Result{Output: output}, err
+	})
+
+	result.Variant = variant
+
+	return result, err
+}
+
+// Initializes the solver once and solves the part with every variant,
+// one after another so their times can be compared
+// Returns error if the solver is unknown, the part has no variants or
+// the initialization failed
+func CrosscheckPart(ctx context.Context, name string, part int, reader io.Reader) (Crosscheck, error) {
+	item, ok := Lookup(name)
+
+	if !ok {
+		return Crosscheck{}, fmt.Errorf("%s: %w", name, ErrUnknownSolver)
+	}
+
+	variants := item.Metadata.Variants(part)
+
+	if len(variants) == 0 {
+		return Crosscheck{}, fmt.Errorf("%s part %d has no variants: %w", name, part, ErrUnknownVariant)
+	}
+
+	s := guard(name, WithCtx(item.Constructor()))
+
+	start := time.Now()
+
+	if err := s.InitCtx(ctx, reader); err != nil {
+		return Crosscheck{}, err
+	}
+
+	parseTime := time.Since(start)
+
+	c := Crosscheck{Part: part, Results: make([]VariantResult, 0, len(variants))}
+
+	for _, variant := range variants {
+		result, err := SolveVariant(ctx, s, part, variant)
+
+		result.Part = part
+		result.Variant = variant
+		result.ParseTime = parseTime
+
+		c.Results = append(c.Results, VariantResult{Result: result, Err: err})
+	}
+
+	reference, _ := c.Reference()
+
+	for _, r := range c.Results {
+		switch {
+		case r.Err != nil:
+			c.Failed = append(c.Failed, r.Variant)
+		case r.Output != reference.Output:
+			c.Disagree = append(c.Disagree, r.Variant)
+		}
+	}
+
+	return c, nil
+}
+
+// Returns result of the first variant which succeeded, false if all failed
+func (c Crosscheck) Reference() (VariantResult, bool) {
+	for _, r := range c.Results {
+		if r.Err == nil {
+			return r, true
+		}
+	}
+
+	return VariantResult{}, false
+}
+
+// Checks if all variants succeeded with the same output
+func (c Crosscheck) Agree() bool {
+	return len(c.Disagree) == 0 && len(c.Failed) == 0
+}
@@ -0,0 +1,169 @@
+// Package provides fuzzing of registered solvers
+package solvertest
+
+import (
+	"advent2024/pkg/solver"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+//go:generate go run gen_fuzz.go
+
+// Deadline of initialising and solving all parts of the fuzzed input
+const FuzzTimeout = time.Second
+
+// Time the solver has to return after the deadline before it is
+// reported as hanging
+const hangGrace = time.Second
+
+// Error of the solver which did not return after the deadline
+var errHang = errors.New("solver still running after the deadline")
+
+// Set once a hanging solver was left running, later inputs are skipped
+// instead of piling up more running solvers
+var abandoned atomic.Bool
+
+// Errors the solvers are expected to fail with
+var sentinels = []error{
+	solver.ErrInvalidInput,
+	solver.ErrTimeout,
+	solver.ErrUnknownPart,
+	solver.ErrNoMoreSteps,
+	solver.ErrUnknownSolver,
+}
+
+// Inputs of broken shape added to every seed corpus
+var fuzzSeeds = []string{
+	"",
+	"\n\n",
+	"x",
+	"..\n...",
+	"1 2\n3",
+	"-1",
+	"99999999999999999999",
+}
+
+// Fuzzes Init and Solve of every part of the solver with arbitrary input
+// Seeds are the example, golden cases of the day and inputs of broken shape
+// Fails on panic, error which does not wrap a solver sentinel error or
+// solver which does not return after the deadline
+func Fuzz(f *testing.F, name string) {
+	item, ok := solver.Lookup(name)
+
+	if !ok {
+		f.Fatalf("%s not registered", name)
+	}
+
+	f.Add([]byte(item.Metadata.Example))
+
+	// golden cases are kept in solvertest next to the day packages
+	cases, _ := LoadGolden(filepath.Join("..", "solvertest", "testdata"))
+
+	for _, c := range cases {
+		if c.Day == name {
+			f.Add([]byte(c.Input))
+		}
+	}
+
+	for _, s := range fuzzSeeds {
+		f.Add([]byte(s))
+	}
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		if abandoned.Load() {
+			t.Skip("hanging solver of an earlier input is still running")
+		}
+
+		if err := Crash(name, input, FuzzTimeout); err != nil {
+			t.Fatalf("input %q: %v", input, err)
+		}
+	})
+}
+
+// Initialises the solver with the input and solves every part
+// Solving which gives up with ErrTimeout is not a crash, solving which
+// does not return within hangGrace after timeout is
+// Returns error if the solver panics, hangs or fails with error which
+// does not wrap a solver sentinel error
+func Crash(name string, input []byte, timeout time.Duration) error {
+	item, ok := solver.Lookup(name)
+
+	if !ok {
+		return fmt.Errorf("%s not registered", name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	s := solver.WithCtx(item.Constructor())
+
+	err := guard(ctx, func() error {
+		return s.InitCtx(ctx, strings.NewReader(string(input)))
+	})
+
+	if err != nil {
+		return unexpected(name, "init", err)
+	}
+
+	for _, p := range item.Metadata.Parts {
+		err := guard(ctx, func() error {
+			_, err := s.SolveCtx(ctx, p.Part)
+			return err
+		})
+
+		if err != nil {
+			return unexpected(name, fmt.Sprintf("part %d", p.Part), err)
+		}
+	}
+
+	return nil
+}
+
+// Runs fn, converts panic into error
+// Gives up with errHang when fn does not return within hangGrace after
+// ctx is done, fn is left running then
+func guard(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+			}
+		}()
+
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(hangGrace):
+		abandoned.Store(true)
+		return errHang
+	}
+}
+
+// Returns err annotated with the stage if it does not wrap a sentinel
+func unexpected(name, stage string, err error) error {
+	for _, sentinel := range sentinels {
+		if errors.Is(err, sentinel) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s %s: %w", name, stage, err)
+}
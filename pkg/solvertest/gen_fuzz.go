@@ -0,0 +1,71 @@
+//go:build ignore
+
+// Generates fuzz target of every day package next to solvertest
+// Run with go generate in solvertest
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"text/template"
+)
+
+// Day packages declare the name they register with
+var dayRe = regexp.MustCompile(`(?m)^var day = "(\w+)"`)
+
+var fuzzTemplate = template.Must(template.New("fuzz").Parse(`// Code generated by gen_fuzz.go; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"advent2024/pkg/solvertest"
+	"testing"
+)
+
+// Fuzzes Init and Solve of every part, run with go test -fuzz FuzzSolver
+func FuzzSolver(f *testing.F) {
+	solvertest.Fuzz(f, day)
+}
+`))
+
+func main() {
+	sources, err := filepath.Glob(filepath.Join("..", "d*", "d*.go"))
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, source := range sources {
+		b, err := os.ReadFile(source)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if !dayRe.Match(b) {
+			continue
+		}
+
+		dir := filepath.Dir(source)
+
+		var buf bytes.Buffer
+
+		if err := fuzzTemplate.Execute(&buf, struct{ Package string }{filepath.Base(dir)}); err != nil {
+			log.Fatal(err)
+		}
+
+		code, err := format.Source(buf.Bytes())
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, "fuzz_test.go"), code, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module advent2024/pkg/solvertest
+
+go 1.22.2
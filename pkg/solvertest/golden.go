@@ -0,0 +1,238 @@
+// Package provides golden answer regression cases
+package solvertest
+
+import (
+	"advent2024/pkg/solver"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Extension of the input file and of the sidecar file with answers
+const (
+	InputExt   = ".txt"
+	AnswersExt = ".answers"
+)
+
+// Interfaces the golden cases are solved through
+const (
+	ModePlain = "plain"
+	ModeCtx   = "ctx"
+)
+
+// Outcome of solving a part of the golden case
+type Outcome string
+
+const (
+	Pass    Outcome = "pass"
+	Fail    Outcome = "fail"
+	Timeout Outcome = "timeout"
+)
+
+// Input of the day with expected answers of the parts
+// Stored as <dir>/<day>/<name>.txt with sidecar <name>.answers
+// holding one "part: answer" per line
+type GoldenCase struct {
+	Day     string
+	Name    string
+	Input   string
+	Answers map[int]string
+}
+
+// Result of solving a part of the golden case through one interface
+type GoldenResult struct {
+	Day     string
+	Name    string
+	Part    int
+	Mode    string
+	Outcome Outcome
+	Got     string
+	Err     error
+}
+
+// Loads golden cases of every day directory in dir
+// Returns error if an input lacks its answers or answers are malformed
+func LoadGolden(dir string) ([]GoldenCase, error) {
+	inputs, err := filepath.Glob(filepath.Join(dir, "*", "*"+InputExt))
+
+	if err != nil {
+		return nil, err
+	}
+
+	cases := make([]GoldenCase, 0, len(inputs))
+
+	for _, path := range inputs {
+		input, err := os.ReadFile(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		base := strings.TrimSuffix(path, InputExt)
+		answers, err := loadAnswers(base + AnswersExt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		cases = append(cases, GoldenCase{
+			Day:     filepath.Base(filepath.Dir(path)),
+			Name:    filepath.Base(base),
+			Input:   string(input),
+			Answers: answers,
+		})
+	}
+
+	return cases, nil
+}
+
+// Reads "part: answer" lines, empty lines and # comments are skipped
+func loadAnswers(path string) (map[int]string, error) {
+	fh, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer fh.Close()
+
+	answers := make(map[int]string)
+	sc := bufio.NewScanner(fh)
+
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		partStr, answer, ok := strings.Cut(line, ":")
+		part, err := strconv.Atoi(strings.TrimSpace(partStr))
+
+		if !ok || err != nil {
+			return nil, fmt.Errorf("%s line %d: expected part: answer", path, n)
+		}
+
+		answers[part] = strings.TrimSpace(answer)
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(answers) == 0 {
+		return nil, fmt.Errorf("%s: no answers", path)
+	}
+
+	return answers, nil
+}
+
+// Solves every part of the case through the plain and the context
+// interface, each part on a fresh instance under the timeout
+// Returns results ordered by part, plain before ctx
+func RunGolden(c GoldenCase, timeout time.Duration) []GoldenResult {
+	parts := make([]int, 0, len(c.Answers))
+
+	for part := range c.Answers {
+		parts = append(parts, part)
+	}
+
+	slices.Sort(parts)
+
+	results := make([]GoldenResult, 0, 2*len(parts))
+
+	for _, part := range parts {
+		for _, mode := range []string{ModePlain, ModeCtx} {
+			got, err := solveGolden(c, part, mode, timeout)
+
+			r := GoldenResult{Day: c.Day, Name: c.Name, Part: part, Mode: mode, Got: got, Err: err}
+
+			switch {
+			case errors.Is(err, solver.ErrTimeout):
+				r.Outcome = Timeout
+			case err == nil && got == c.Answers[part]:
+				r.Outcome = Pass
+			default:
+				r.Outcome = Fail
+			}
+
+			results = append(results, r)
+		}
+	}
+
+	return results
+}
+
+// Solves the part on a fresh instance through the interface of the mode
+func solveGolden(c GoldenCase, part int, mode string, timeout time.Duration) (string, error) {
+	item, ok := solver.Lookup(c.Day)
+
+	if !ok {
+		return "", fmt.Errorf("%s: %w", c.Day, solver.ErrUnknownSolver)
+	}
+
+	if mode == ModePlain {
+		s := item.Constructor()
+
+		if err := s.Init(strings.NewReader(c.Input)); err != nil {
+			return "", err
+		}
+
+		return solveWithin(s, part, timeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	s := solver.WithCtx(item.Constructor())
+
+	if err := s.InitCtx(ctx, strings.NewReader(c.Input)); err != nil {
+		return "", err
+	}
+
+	return s.SolveCtx(ctx, part)
+}
+
+// Renders results as a table, one row per case and part with
+// outcome of every interface
+func Matrix(results []GoldenResult) string {
+	type row struct {
+		day, name string
+		part      int
+	}
+
+	var rows []row
+	outcomes := make(map[row]map[string]Outcome)
+
+	for _, r := range results {
+		k := row{r.Day, r.Name, r.Part}
+
+		if _, ok := outcomes[k]; !ok {
+			rows = append(rows, k)
+			outcomes[k] = make(map[string]Outcome)
+		}
+
+		outcomes[k][r.Mode] = r.Outcome
+	}
+
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "day\tcase\tpart\t%s\t%s\n", ModePlain, ModeCtx)
+
+	for _, k := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", k.day, k.name, k.part, outcomes[k][ModePlain], outcomes[k][ModeCtx])
+	}
+
+	tw.Flush()
+
+	return sb.String()
+}
@@ -0,0 +1,189 @@
+// Package provides checks shared by all registered solvers
+package solvertest
+
+import (
+	"advent2024/pkg/solver"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Time limit of a single solve, solvers working with mutated state
+// may never finish
+const SolveTimeout = 10 * time.Second
+
+// Order in which parts are solved on a single instance
+type order struct {
+	name  string
+	parts []int
+}
+
+// Checks that solving does not change the initialised state
+// Every part is solved on a fresh instance first, the answers are then
+// compared with solving the parts on one instance in order, in reverse
+// and repeatedly, both with Solve and SolveCtx, and with SolveAll
+// Returns nil if all the answers match
+func Idempotent(name, input string) error {
+	item, ok := solver.Lookup(name)
+
+	if !ok {
+		return fmt.Errorf("%s not registered", name)
+	}
+
+	parts := make([]int, 0, len(item.Metadata.Parts))
+
+	for _, p := range item.Metadata.Parts {
+		parts = append(parts, p.Part)
+	}
+
+	// reference answers, one instance per part
+	want := make(map[int]string, len(parts))
+
+	for _, part := range parts {
+		s := item.Constructor()
+
+		if err := s.Init(strings.NewReader(input)); err != nil {
+			return fmt.Errorf("%s unable to init: %w", name, err)
+		}
+
+		got, err := solveWithin(s, part, SolveTimeout)
+
+		if err != nil {
+			return fmt.Errorf("%s unable to solve part %d: %w", name, part, err)
+		}
+
+		want[part] = got
+	}
+
+	reversed := slices.Clone(parts)
+	slices.Reverse(reversed)
+
+	orders := []order{
+		{"in order", parts},
+		{"reversed", reversed},
+		{"repeated", slices.Concat(parts, parts)},
+	}
+
+	for _, o := range orders {
+		s := item.Constructor()
+
+		if err := s.Init(strings.NewReader(input)); err != nil {
+			return fmt.Errorf("%s unable to init: %w", name, err)
+		}
+
+		for _, part := range o.parts {
+			got, err := solveWithin(s, part, SolveTimeout)
+
+			if err != nil {
+				return fmt.Errorf("%s %s: unable to solve part %d: %w", name, o.name, part, err)
+			}
+
+			if got != want[part] {
+				return fmt.Errorf("%s %s: part %d got %s expected %s", name, o.name, part, got, want[part])
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), SolveTimeout*time.Duration(len(o.parts)))
+		defer cancel()
+
+		sc := solver.WithCtx(item.Constructor())
+
+		if err := sc.InitCtx(ctx, strings.NewReader(input)); err != nil {
+			return fmt.Errorf("%s unable to init: %w", name, err)
+		}
+
+		for _, part := range o.parts {
+			got, err := sc.SolveCtx(ctx, part)
+
+			if err != nil {
+				return fmt.Errorf("%s %s with context: unable to solve part %d: %w", name, o.name, part, err)
+			}
+
+			if got != want[part] {
+				return fmt.Errorf("%s %s with context: part %d got %s expected %s", name, o.name, part, got, want[part])
+			}
+		}
+	}
+
+	// all parts at once on a single instance
+	ctx, cancel := context.WithTimeout(context.Background(), SolveTimeout)
+	defer cancel()
+
+	results, err := solver.SolveAll(ctx, name, strings.NewReader(input))
+
+	if err != nil {
+		return fmt.Errorf("%s concurrently: %w", name, err)
+	}
+
+	for _, r := range results {
+		if r.Err != nil {
+			return fmt.Errorf("%s concurrently: unable to solve part %d: %w", name, r.Part, r.Err)
+		}
+
+		if r.Output != want[r.Part] {
+			return fmt.Errorf("%s concurrently: part %d got %s expected %s", name, r.Part, r.Output, want[r.Part])
+		}
+	}
+
+	return nil
+}
+
+// Checks answers for the example of the solver metadata
+// Parts without example answer are skipped
+// Returns nil if all the answers match
+func Examples(name string) error {
+	item, ok := solver.Lookup(name)
+
+	if !ok {
+		return fmt.Errorf("%s not registered", name)
+	}
+
+	for _, p := range item.Metadata.Parts {
+		if p.ExampleAnswer == "" {
+			continue
+		}
+
+		s := item.Constructor()
+
+		if err := s.Init(strings.NewReader(item.Metadata.Example)); err != nil {
+			return fmt.Errorf("%s unable to init: %w", name, err)
+		}
+
+		got, err := solveWithin(s, p.Part, SolveTimeout)
+
+		if err != nil {
+			return fmt.Errorf("%s unable to solve part %d: %w", name, p.Part, err)
+		}
+
+		if got != p.ExampleAnswer {
+			return fmt.Errorf("%s part %d got %s expected %s", name, p.Part, got, p.ExampleAnswer)
+		}
+	}
+
+	return nil
+}
+
+// Solves the part, gives up after timeout
+// The solving goroutine is left running in that case
+func solveWithin(s solver.PuzzleSolver, part int, timeout time.Duration) (string, error) {
+	type answer struct {
+		result string
+		err    error
+	}
+
+	done := make(chan answer, 1)
+
+	go func() {
+		result, err := s.Solve(part)
+		done <- answer{result, err}
+	}()
+
+	select {
+	case a := <-done:
+		return a.result, a.err
+	case <-time.After(timeout):
+		return "", solver.ErrTimeout
+	}
+}
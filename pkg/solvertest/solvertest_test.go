@@ -0,0 +1,225 @@
+package solvertest
+
+import (
+	"advent2024/pkg/solver"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	_ "advent2024/pkg/d0"
+	_ "advent2024/pkg/d1"
+	_ "advent2024/pkg/d10"
+	_ "advent2024/pkg/d11"
+	_ "advent2024/pkg/d2"
+	_ "advent2024/pkg/d3"
+	_ "advent2024/pkg/d4"
+	_ "advent2024/pkg/d5"
+	_ "advent2024/pkg/d6"
+	_ "advent2024/pkg/d7"
+	_ "advent2024/pkg/d8"
+	_ "advent2024/pkg/d9"
+)
+
+func TestIdempotent(t *testing.T) {
+	for _, item := range solver.ListRegistryItems() {
+		t.Run(item.Name, func(t *testing.T) {
+			if item.Example == "" {
+				t.Skip("no example")
+			}
+
+			if err := Idempotent(item.Name, item.Example); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestExamples(t *testing.T) {
+	for _, item := range solver.ListRegistryItems() {
+		t.Run(item.Name, func(t *testing.T) {
+			if item.Example == "" {
+				t.Skip("no example")
+			}
+
+			if err := Examples(item.Name); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// Golden cases, add a case by dropping <name>.txt and <name>.answers
+// into testdata/<day>
+func TestGolden(t *testing.T) {
+	cases, err := LoadGolden("testdata")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var all []GoldenResult
+
+	for _, c := range cases {
+		t.Run(c.Day+"/"+c.Name, func(t *testing.T) {
+			results := RunGolden(c, SolveTimeout)
+			all = append(all, results...)
+
+			for _, r := range results {
+				if r.Outcome != Pass {
+					t.Errorf("part %d %s: %s got %q expected %q, %v", r.Part, r.Mode, r.Outcome, r.Got, c.Answers[r.Part], r.Err)
+				}
+			}
+		})
+	}
+
+	t.Logf("\n%s", Matrix(all))
+
+	for _, item := range solver.ListRegistryItems() {
+		if !slices.ContainsFunc(cases, func(c GoldenCase) bool { return c.Day == item.Name }) {
+			t.Logf("%s has no golden cases", item.Name)
+		}
+	}
+}
+
+func TestGoldenOutcomes(t *testing.T) {
+	c := GoldenCase{Day: "d1", Name: "wrong", Input: "3 4\n4 3\n", Answers: map[int]string{1: "1", 3: "0"}}
+	missing := GoldenCase{Day: "d99", Name: "missing", Answers: map[int]string{1: "0"}}
+
+	results := append(RunGolden(c, SolveTimeout), RunGolden(missing, SolveTimeout)...)
+
+	if len(results) != 6 {
+		t.Fatalf("got %d results expected 6", len(results))
+	}
+
+	for _, r := range results {
+		if r.Outcome != Fail {
+			t.Errorf("%s part %d %s: got %s expected %s", r.Day, r.Part, r.Mode, r.Outcome, Fail)
+		}
+	}
+
+	if r := results[0]; r.Part != 1 || r.Mode != ModePlain || r.Got != "0" {
+		t.Errorf("got %+v expected plain part 1 first", r)
+	}
+
+	want := "day  case     part  plain  ctx\nd1   wrong    1     fail   fail\nd1   wrong    3     fail   fail\nd99  missing  1     fail   fail\n"
+
+	if got := Matrix(results); got != want {
+		t.Errorf("got\n%s expected\n%s", got, want)
+	}
+}
+
+func TestLoadGolden(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "d1"), 0o755)
+	os.WriteFile(filepath.Join(dir, "d1", "case.txt"), []byte("3 4\n"), 0o644)
+
+	if _, err := LoadGolden(dir); err == nil {
+		t.Errorf("missing answers not detected")
+	}
+
+	os.WriteFile(filepath.Join(dir, "d1", "case.answers"), []byte("# comment\n\n1: 1\n2:  12 \n"), 0o644)
+
+	cases, err := LoadGolden(dir)
+
+	if err != nil || len(cases) != 1 || cases[0].Day != "d1" || cases[0].Name != "case" {
+		t.Fatalf("got %+v, %v", cases, err)
+	}
+
+	if !reflect.DeepEqual(cases[0].Answers, map[int]string{1: "1", 2: "12"}) {
+		t.Errorf("got %v", cases[0].Answers)
+	}
+
+	os.WriteFile(filepath.Join(dir, "d1", "case.answers"), []byte("part one\n"), 0o644)
+
+	if _, err := LoadGolden(dir); err == nil {
+		t.Errorf("malformed answers not detected")
+	}
+}
+
+// Solver counting the calls in its state
+type mutatingSolver struct {
+	calls int
+}
+
+func (p *mutatingSolver) Init(reader io.Reader) error {
+	return nil
+}
+
+func (p *mutatingSolver) Solve(part int) (string, error) {
+	p.calls++
+	return strconv.Itoa(p.calls), nil
+}
+
+func TestIdempotentDetectsMutation(t *testing.T) {
+	solver.Register("test-mutating", func() solver.PuzzleSolver { return &mutatingSolver{} })
+
+	if err := Idempotent("test-mutating", ""); err == nil {
+		t.Errorf("mutation not detected")
+	}
+}
+
+// Solver failing in the way selected by the input
+type crashingSolver struct {
+	input string
+}
+
+func (p *crashingSolver) Init(reader io.Reader) error {
+	b, _ := io.ReadAll(reader)
+	p.input = string(b)
+
+	if p.input == "invalid" {
+		return fmt.Errorf("test: %w", solver.ErrInvalidInput)
+	}
+
+	return nil
+}
+
+func (p *crashingSolver) Solve(part int) (string, error) {
+	switch p.input {
+	case "panic":
+		var field [][]byte
+		return string(field[1][2]), nil
+	case "error":
+		return "", errors.New("unexpected")
+	case "slow":
+		time.Sleep(300 * time.Millisecond)
+	case "hang":
+		time.Sleep(time.Minute)
+	}
+
+	return "", nil
+}
+
+func TestCrash(t *testing.T) {
+	solver.Register("test-crashing", func() solver.PuzzleSolver { return &crashingSolver{} })
+	defer abandoned.Store(false)
+
+	cases := []struct {
+		input string
+		crash bool
+	}{
+		{"valid", false},
+		{"invalid", false},
+		{"slow", false},
+		{"hang", true},
+		{"panic", true},
+		{"error", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			err := Crash("test-crashing", []byte(c.input), 100*time.Millisecond)
+
+			if (err != nil) != c.crash {
+				t.Errorf("got %v expected crash %t", err, c.crash)
+			}
+		})
+	}
+}
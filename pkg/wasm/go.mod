@@ -0,0 +1,5 @@
+module advent2024/pkg/wasm
+
+go 1.22.2
+
+require github.com/tetratelabs/wazero v1.9.0
@@ -0,0 +1,172 @@
+// Package runs solvers compiled to WebAssembly (wasip1) in a sandbox
+// Modules speak the external solver protocol over stdin and stdout,
+// every operation runs in a new module instance with limited memory and time
+package wasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"advent2024/pkg/solver"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+// Size of the WebAssembly memory page
+const pageSize = 64 * 1024
+
+// Sandbox limits
+type Config struct {
+	// Maximum memory of one module instance in bytes
+	MemoryLimit uint64
+	// Maximum run time of one operation, 0 means limited by the context only
+	Timeout time.Duration
+}
+
+// Runtime running WebAssembly solvers
+// Modules are compiled once and instantiated for every operation, so
+// instances can run concurrently and nothing is kept between operations
+type Runtime struct {
+	rt     wazero.Runtime
+	config Config
+}
+
+// Constructor with defaults
+func NewConfig() Config {
+	return Config{
+		MemoryLimit: 256 * 1024 * 1024,
+		Timeout:     time.Minute,
+	}
+}
+
+// Constructor
+func NewRuntime(ctx context.Context, config Config) (*Runtime, error) {
+	if config.MemoryLimit < pageSize {
+		return nil, fmt.Errorf("memory limit %d lower than page size %d", config.MemoryLimit, pageSize)
+	}
+
+	rc := wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(uint32(min(config.MemoryLimit/pageSize, 65536)))
+
+	rt := wazero.NewRuntimeWithConfig(ctx, rc)
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, rt); err != nil {
+		rt.Close(ctx)
+		return nil, fmt.Errorf("unable to instantiate WASI: %w", err)
+	}
+
+	return &Runtime{rt: rt, config: config}, nil
+}
+
+// Closes the runtime, solvers registered from the runtime stop working
+func (r *Runtime) Close(ctx context.Context) error {
+	return r.rt.Close(ctx)
+}
+
+// Loads .wasm files in the directory as solvers
+// Solvers are registered under the name reported by the module,
+// names of already registered solvers are not replaced
+// Returns registered names and errors of the modules which failed to load
+func (r *Runtime) Load(ctx context.Context, dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read wasm directory %s: %w", dir, err)
+	}
+
+	var names []string
+	var errs []error
+
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") || filepath.Ext(e.Name()) != ".wasm" {
+			continue
+		}
+
+		name, err := r.Register(ctx, filepath.Join(dir, e.Name()))
+
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	return names, errors.Join(errs...)
+}
+
+// Compiles the module and registers it as solver
+// Returns registered name
+func (r *Runtime) Register(ctx context.Context, path string) (string, error) {
+	b, err := os.ReadFile(path)
+
+	if err != nil {
+		return "", fmt.Errorf("unable to read wasm module %s: %w", path, err)
+	}
+
+	compiled, err := r.rt.CompileModule(ctx, b)
+
+	if err != nil {
+		return "", fmt.Errorf("unable to compile wasm module %s: %w", path, err)
+	}
+
+	name, err := solver.RegisterExternal(ctx, path, r.runner(compiled))
+
+	if err != nil {
+		compiled.Close(ctx)
+		return "", err
+	}
+
+	return name, nil
+}
+
+// Returns runner instantiating the compiled module
+// Closing the instance on context cancellation is left to the runtime
+func (r *Runtime) runner(compiled wazero.CompiledModule) solver.ExternalRunner {
+	return func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer) error {
+		if r.config.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, r.config.Timeout)
+			defer cancel()
+		}
+
+		mc := wazero.NewModuleConfig().
+			WithName("").
+			WithStdin(stdin).
+			WithStdout(stdout).
+			WithStderr(stderr).
+			WithSysWalltime().
+			WithSysNanotime().
+			WithSysNanosleep()
+
+		mod, err := r.rt.InstantiateModule(ctx, compiled, mc)
+
+		if mod != nil {
+			mod.Close(ctx)
+		}
+
+		var exitErr *sys.ExitError
+
+		switch {
+		case ctx.Err() != nil:
+			return solver.ErrTimeout
+		case errors.As(err, &exitErr) && exitErr.ExitCode() == sys.ExitCodeDeadlineExceeded:
+			return solver.ErrTimeout
+		case errors.As(err, &exitErr) && exitErr.ExitCode() == sys.ExitCodeContextCanceled:
+			return solver.ErrTimeout
+		case err != nil:
+			return fmt.Errorf("module failed: %w", err)
+		}
+
+		return nil
+	}
+}
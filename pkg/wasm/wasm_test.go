@@ -0,0 +1,151 @@
+package wasm
+
+import (
+	"advent2024/pkg/solver"
+	"advent2024/pkg/solvertest"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Directory with the d6 module built by buildModule
+var moduleDir string
+
+// Builds d6 day package as wasip1 module, the build takes a while
+func buildModule(t *testing.T) string {
+	if testing.Short() {
+		t.Skip("building wasm module skipped in short mode")
+	}
+
+	if moduleDir != "" {
+		return moduleDir
+	}
+
+	dir, err := os.MkdirTemp("", "wasm")
+
+	if err != nil {
+		t.Fatalf("unable to create directory: %v", err)
+	}
+
+	cmd := exec.Command("go", "build", "-ldflags=-X main.Day=d6", "-o", filepath.Join(dir, "d6.wasm"), "../../cmd/wasm")
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm", "GOFLAGS=")
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("unable to build module: %v: %s", err, out)
+	}
+
+	moduleDir = dir
+
+	return dir
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+
+	if moduleDir != "" {
+		os.RemoveAll(moduleDir)
+	}
+
+	os.Exit(code)
+}
+
+func TestLoad(t *testing.T) {
+	dir := buildModule(t)
+	ctx := context.Background()
+
+	rt, err := NewRuntime(ctx, NewConfig())
+
+	if err != nil {
+		t.Fatalf("got %v expected nil", err)
+	}
+
+	names, err := rt.Load(ctx, dir)
+
+	if err != nil || len(names) != 1 || names[0] != "d6" {
+		t.Fatalf("got %v, %v expected [d6]", names, err)
+	}
+
+	item, _ := solver.Lookup("d6")
+
+	t.Run("examples", func(t *testing.T) {
+		if err := solvertest.Examples("d6"); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("idempotent", func(t *testing.T) {
+		if err := solvertest.Idempotent("d6", item.Metadata.Example); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		s, _ := solver.NewWithCtx("d6")
+		_ = s.InitCtx(ctx, strings.NewReader(item.Metadata.Example))
+
+		result, err := solver.SolvePart(ctx, s, 1)
+
+		if err != nil || result.Stats["visited"] != 41 {
+			t.Errorf("got %+v, %v expected visited 41", result, err)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		s, _ := solver.NewWithCtx("d6")
+
+		if err := s.InitCtx(ctx, strings.NewReader("x")); !errors.Is(err, solver.ErrInvalidInput) {
+			t.Errorf("got %v expected %v", err, solver.ErrInvalidInput)
+		}
+	})
+
+	t.Run("cancellation closes the module", func(t *testing.T) {
+		s, _ := solver.NewWithCtx("d6")
+		_ = s.InitCtx(ctx, strings.NewReader(item.Metadata.Example))
+
+		ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+		defer cancel()
+
+		if _, err := s.SolveCtx(ctx, 2); !errors.Is(err, solver.ErrTimeout) {
+			t.Errorf("got %v expected %v", err, solver.ErrTimeout)
+		}
+	})
+}
+
+func TestLimits(t *testing.T) {
+	dir := buildModule(t)
+	ctx := context.Background()
+	path := filepath.Join(dir, "d6.wasm")
+
+	tests := []struct {
+		name   string
+		config Config
+		err    error
+	}{
+		{"timeout", Config{MemoryLimit: NewConfig().MemoryLimit, Timeout: time.Nanosecond}, solver.ErrTimeout},
+		{"memory below module minimum", Config{MemoryLimit: pageSize}, nil},
+		{"memory exhausted", Config{MemoryLimit: 4 * 1024 * 1024}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rt, err := NewRuntime(ctx, tc.config)
+
+			if err != nil {
+				t.Fatalf("got %v expected nil", err)
+			}
+
+			defer rt.Close(ctx)
+
+			_, err = rt.Register(ctx, path)
+
+			if err == nil || (tc.err != nil && !errors.Is(err, tc.err)) {
+				t.Errorf("got %v expected %v", err, tc.err)
+			}
+		})
+	}
+}